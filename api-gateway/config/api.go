@@ -2,11 +2,15 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 type server struct {
 	HTTPHost string
 	HTTPPort string
+	// TrustedProxies are the addresses allowed to set the client address
+	// through X-Forwarded-For, none when empty
+	TrustedProxies []string
 }
 
 type logger struct {
@@ -22,10 +26,16 @@ type Settings struct {
 // GetSettings returns the settings
 func GetSettings() *Settings {
 
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+
 	Settings := &Settings{
 		Server: &server{
-			HTTPHost: os.Getenv("HTTP_HOST"),
-			HTTPPort: os.Getenv("HTTP_PORT"),
+			HTTPHost:       os.Getenv("HTTP_HOST"),
+			HTTPPort:       os.Getenv("HTTP_PORT"),
+			TrustedProxies: trustedProxies,
 		},
 
 		Logger: &logger{
@@ -1,23 +1,54 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iamvasanth07/showcase/api-gateway/config"
+	"github.com/iamvasanth07/showcase/api-gateway/middleware"
 	"github.com/iamvasanth07/showcase/api-gateway/routes"
+	channelConfig "github.com/iamvasanth07/showcase/channel/config"
 	userConfig "github.com/iamvasanth07/showcase/user/config"
 	videoConfig "github.com/iamvasanth07/showcase/video/config"
 )
 
+// denylistInterval is the delay before a revoked token is rejected
+const denylistInterval = 5 * time.Second
+
 // serve the user routes using gin
 
 func main() {
 	settings := config.GetSettings()
-	userRoutes := routes.NewUserRoutes(userConfig.GetSettings())
-	videoRoutes := routes.NewVideoRoutes(videoConfig.GetSettings())
+	// the gateway checks the signatures of the playback urls minted by the
+	// video service, it shares its secrets
+	videoSettings := videoConfig.GetSettings()
+	if missing := videoSettings.MissingSecrets(); len(missing) > 0 {
+		log.Fatalf("refusing to start without the secrets %s", strings.Join(missing, ", "))
+	}
+	userSettings := userConfig.GetSettings()
+	userRoutes := routes.NewUserRoutes(userSettings)
+	denylist := middleware.NewDenylist(userRoutes.Client(), log.New(os.Stdout, "api-gateway: ", log.LstdFlags))
+	go denylist.Run(context.Background(), denylistInterval)
+	jwksRoutes := routes.NewJWKSRoutes(userRoutes.Client())
+	authn := middleware.NewAuthenticator(jwksRoutes.Keys(), userSettings.JWT.Issuer, denylist, userRoutes.Client())
+	videoRoutes := routes.NewVideoRoutes(videoSettings)
+	channelRoutes := routes.NewChannelRoutes(channelConfig.GetSettings())
+	feedRoutes := routes.NewFeedRoutes(channelRoutes, videoRoutes)
 	r := gin.Default()
-	userRoutes.RegisterUserSvcRoutes(r)
-	videoRoutes.RegisterVideoSvcRoutes(r)
+	// the client address counts the failed logins, it can only be forwarded
+	// by the trusted proxies
+	if err := r.SetTrustedProxies(settings.Server.TrustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %v", err)
+	}
+	jwksRoutes.RegisterJWKSRoutes(r)
+	userRoutes.RegisterUserSvcRoutes(r, authn)
+	videoRoutes.RegisterVideoSvcRoutes(r, authn)
+	channelRoutes.RegisterChannelSvcRoutes(r, authn)
+	feedRoutes.RegisterFeedRoutes(r, authn)
 	r.Run(fmt.Sprintf("%s:%s", settings.Server.HTTPHost, settings.Server.HTTPPort))
 }
@@ -0,0 +1,246 @@
+// authentication middleware for the api gateway
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/jwks"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// identityKey is the gin context key holding the authenticated *auth.Identity
+const identityKey = "identity"
+
+// Authenticator validates the bearer tokens minted by the user service
+// against the public keys of its key set, and the personal access tokens
+// through the user service
+type Authenticator struct {
+	keys     *jwks.Cache
+	issuer   string
+	denylist *Denylist
+	tokens   pb.UserServiceClient
+}
+
+// NewAuthenticator returns a new authenticator rejecting the tokens of the
+// denylist and resolving the personal access tokens with the user client
+func NewAuthenticator(keys *jwks.Cache, issuer string, denylist *Denylist, tokens pb.UserServiceClient) *Authenticator {
+	return &Authenticator{
+		keys:     keys,
+		issuer:   issuer,
+		denylist: denylist,
+		tokens:   tokens,
+	}
+}
+
+// Required rejects requests without a valid bearer token. Personal access
+// tokens are accepted when they hold one of the scopes, the routes without
+// scopes are reserved to the jwts.
+func (a *Authenticator) Required(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := a.authenticate(c)
+		if err != nil {
+			reject(c, err)
+			return
+		}
+		if !allowedScopes(identity, scopes) {
+			response.Forbidden(c, "the personal access token lacks the scope of this route")
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// Optional authenticates the request when a bearer token is present, the
+// personal access tokens must hold one of the scopes
+func (a *Authenticator) Optional(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		identity, err := a.authenticate(c)
+		if err != nil {
+			reject(c, err)
+			return
+		}
+		if !allowedScopes(identity, scopes) {
+			response.Forbidden(c, "the personal access token lacks the scope of this route")
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// reject writes the error of a failed authentication
+func reject(c *gin.Context, err error) {
+	if _, ok := status.FromError(err); ok {
+		response.Error(c, err)
+		return
+	}
+	response.Unauthorized(c, err.Error())
+}
+
+// allowedScopes reports whether the identity may use a route requiring one of the scopes
+func allowedScopes(identity *auth.Identity, scopes []string) bool {
+	if !identity.PersonalAccessToken() {
+		return true
+	}
+	for _, scope := range scopes {
+		if identity.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission rejects the requests of callers whose roles do not grant
+// the permission, it must follow Required
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := GetIdentity(c)
+		if !ok {
+			response.Unauthorized(c, "authentication required")
+			return
+		}
+		if !identity.HasPermission(permission) {
+			response.Forbidden(c, fmt.Sprintf("missing permission %s", permission))
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate parses and verifies the bearer token of the request
+func (a *Authenticator) authenticate(c *gin.Context) (*auth.Identity, error) {
+	req := c.Request
+	header := req.Header.Get("Authorization")
+	if header == "" {
+		return nil, errors.New("missing authorization header")
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, errors.New("invalid authorization header")
+	}
+	if auth.IsPersonalAccessToken(token) {
+		return a.authenticateToken(c, token)
+	}
+	claims := &auth.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("missing key id")
+		}
+		key, alg, err := a.keys.Key(req.Context(), kid)
+		if err != nil {
+			return nil, err
+		}
+		// the algorithm is the one of the key, never the one the token claims
+		if t.Method.Alg() != alg {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, errors.New("invalid or expired token")
+	}
+	if !claims.VerifyIssuer(a.issuer, true) {
+		return nil, errors.New("invalid token issuer")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid token subject")
+	}
+	if a.denylist != nil && a.denylist.Revoked(claims.Id) {
+		return nil, errors.New("token has been revoked")
+	}
+	return auth.IdentityFromClaims(claims), nil
+}
+
+// authenticateToken resolves a personal access token, the user service
+// records its use from the client address
+func (a *Authenticator) authenticateToken(c *gin.Context, token string) (*auth.Identity, error) {
+	res, err := a.tokens.ValidatePersonalAccessToken(c, &pb.ValidatePersonalAccessTokenRequest{
+		Token:    token,
+		ClientIp: c.ClientIP(),
+	})
+	if status.Code(err) == codes.Unauthenticated {
+		return nil, errors.New("invalid or expired token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &auth.Identity{
+		UserID:        res.UserId,
+		Email:         res.Email,
+		EmailVerified: res.EmailVerified,
+		Scopes:        res.Scopes,
+	}, nil
+}
+
+// GetIdentity returns the authenticated identity of the request, if any
+func GetIdentity(c *gin.Context) (*auth.Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil, false
+	}
+	identity, ok := value.(*auth.Identity)
+	return identity, ok
+}
+
+// identityFromContext reads the identity from a gin context passed as a context.Context
+func identityFromContext(ctx context.Context) *auth.Identity {
+	identity, _ := ctx.Value(identityKey).(*auth.Identity)
+	return identity
+}
+
+// clientIPFromContext returns the client address of a gin context passed as a context.Context
+func clientIPFromContext(ctx context.Context) string {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return ""
+	}
+	return c.ClientIP()
+}
+
+// userAgentFromContext returns the client user agent of a gin context passed as a context.Context
+func userAgentFromContext(ctx context.Context) string {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return ""
+	}
+	return c.Request.UserAgent()
+}
+
+// UnaryIdentityInterceptor forwards the authenticated identity and the
+// client address and user agent as grpc metadata
+func UnaryIdentityInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = auth.AppendClientIP(ctx, clientIPFromContext(ctx))
+		ctx = auth.AppendUserAgent(ctx, userAgentFromContext(ctx))
+		ctx = auth.AppendToOutgoingContext(ctx, identityFromContext(ctx))
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamIdentityInterceptor forwards the authenticated identity and the
+// client address and user agent as grpc metadata
+func StreamIdentityInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = auth.AppendClientIP(ctx, clientIPFromContext(ctx))
+		ctx = auth.AppendUserAgent(ctx, userAgentFromContext(ctx))
+		ctx = auth.AppendToOutgoingContext(ctx, identityFromContext(ctx))
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/jwks"
+)
+
+const testIssuer = "showcase-test"
+
+// testKeys are the signing keys of the tests, only the ed25519 key is
+// published in the key set
+type testKeys struct {
+	kid     string
+	private ed25519.PrivateKey
+	other   ed25519.PrivateKey
+	rsa     *rsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testKeys{kid: "key-1", private: private, other: other, rsa: rsaKey}
+}
+
+// cache returns an in-memory key set cache publishing the ed25519 key
+func (k *testKeys) cache(t *testing.T) *jwks.Cache {
+	t.Helper()
+	key, err := jwks.NewKey(k.kid, k.private.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &jwks.Set{Keys: []jwks.Key{key}}
+	return jwks.NewCache(func(ctx context.Context) (*jwks.Set, error) {
+		return set, nil
+	}, time.Hour)
+}
+
+// validClaims returns the claims of a token accepted by the authenticator
+func validClaims() *auth.Claims {
+	now := time.Now()
+	return &auth.Claims{
+		Email:         "ada@example.com",
+		EmailVerified: true,
+		StandardClaims: jwt.StandardClaims{
+			Id:        "jti-1",
+			Subject:   "user-1",
+			Issuer:    testIssuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims *auth.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// newTestRouter serves / behind the middleware, answering with the user id
+// of the identity
+func newTestRouter(middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", middleware, func(c *gin.Context) {
+		identity, ok := GetIdentity(c)
+		if !ok {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, identity.UserID)
+	})
+	return r
+}
+
+func serve(r *gin.Engine, header string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequiredRejectsInvalidTokens(t *testing.T) {
+	keys := newTestKeys(t)
+	denylist := NewDenylist(nil, log.New(io.Discard, "", 0))
+	denylist.tokens["revoked"] = time.Now().Add(time.Hour)
+	authn := NewAuthenticator(keys.cache(t), testIssuer, denylist, nil)
+	r := newTestRouter(authn.Required())
+
+	tests := []struct {
+		name   string
+		header func() string
+		want   int
+	}{
+		{"valid token", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, validClaims())
+		}, http.StatusOK},
+		{"lowercase scheme", func() string {
+			return "bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, validClaims())
+		}, http.StatusOK},
+		{"missing header", func() string { return "" }, http.StatusUnauthorized},
+		{"basic scheme", func() string { return "Basic dXNlcjpwYXNz" }, http.StatusUnauthorized},
+		{"empty token", func() string { return "Bearer " }, http.StatusUnauthorized},
+		{"malformed token", func() string { return "Bearer not.a.jwt" }, http.StatusUnauthorized},
+		{"wrong signature", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.other, validClaims())
+		}, http.StatusUnauthorized},
+		{"expired token", func() string {
+			claims := validClaims()
+			claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, claims)
+		}, http.StatusUnauthorized},
+		{"wrong issuer", func() string {
+			claims := validClaims()
+			claims.Issuer = "someone-else"
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, claims)
+		}, http.StatusUnauthorized},
+		{"missing subject", func() string {
+			claims := validClaims()
+			claims.Subject = ""
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, claims)
+		}, http.StatusUnauthorized},
+		{"unknown kid", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, "key-2", keys.private, validClaims())
+		}, http.StatusUnauthorized},
+		{"missing kid", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, "", keys.private, validClaims())
+		}, http.StatusUnauthorized},
+		{"algorithm mismatch", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodRS256, keys.kid, keys.rsa, validClaims())
+		}, http.StatusUnauthorized},
+		{"hmac with the public key", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodHS256, keys.kid, []byte(keys.private.Public().(ed25519.PublicKey)), validClaims())
+		}, http.StatusUnauthorized},
+		{"unsigned token", func() string {
+			return "Bearer " + sign(t, jwt.SigningMethodNone, keys.kid, jwt.UnsafeAllowNoneSignatureType, validClaims())
+		}, http.StatusUnauthorized},
+		{"denylisted jti", func() string {
+			claims := validClaims()
+			claims.Id = "revoked"
+			return "Bearer " + sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, claims)
+		}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(r, tt.header())
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != "user-1" {
+				t.Errorf("identity = %q, want user-1", rec.Body)
+			}
+		})
+	}
+}
+
+func TestOptional(t *testing.T) {
+	keys := newTestKeys(t)
+	authn := NewAuthenticator(keys.cache(t), testIssuer, nil, nil)
+	r := newTestRouter(authn.Optional())
+
+	rec := serve(r, "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "anonymous" {
+		t.Errorf("without a token: %d %q, want 200 anonymous", rec.Code, rec.Body)
+	}
+	rec = serve(r, "Bearer "+sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.private, validClaims()))
+	if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+		t.Errorf("with a valid token: %d %q, want 200 user-1", rec.Code, rec.Body)
+	}
+	rec = serve(r, "Bearer "+sign(t, jwt.SigningMethodEdDSA, keys.kid, keys.other, validClaims()))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("with an invalid token: %d, want 401", rec.Code)
+	}
+}
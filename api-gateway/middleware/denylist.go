@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+)
+
+// denylistOverlap is subtracted from the time of the previous poll so that
+// the tokens revoked while it ran are not missed
+const denylistOverlap = time.Second
+
+// Denylist keeps the access tokens revoked before they expire, polled from
+// the user service so that checking a token stays a map lookup
+type Denylist struct {
+	client pb.UserServiceClient
+	log    *log.Logger
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	since  string
+}
+
+// NewDenylist returns an empty denylist, Run keeps it up to date
+func NewDenylist(client pb.UserServiceClient, logger *log.Logger) *Denylist {
+	return &Denylist{
+		client: client,
+		log:    logger,
+		tokens: make(map[string]time.Time),
+	}
+}
+
+// Revoked reports whether the token with the jti was revoked
+func (d *Denylist) Revoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.tokens[jti]
+	return ok
+}
+
+// Run polls the revoked tokens every interval until ctx is done
+func (d *Denylist) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := d.sync(ctx); err != nil {
+			d.log.Printf("failed to sync the token denylist: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync adds the tokens revoked since the previous poll and drops the expired ones
+func (d *Denylist) sync(ctx context.Context) error {
+	d.mu.RLock()
+	since := d.since
+	d.mu.RUnlock()
+	res, err := d.client.ListRevokedTokens(ctx, &pb.ListRevokedTokensRequest{Since: since})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, token := range res.Tokens {
+		expiresAt, err := time.Parse(time.RFC3339, token.ExpiresAt)
+		if err != nil {
+			continue
+		}
+		d.tokens[token.Id] = expiresAt
+	}
+	for jti, expiresAt := range d.tokens {
+		if now.After(expiresAt) {
+			delete(d.tokens, jti)
+		}
+	}
+	if asOf, err := time.Parse(time.RFC3339Nano, res.AsOf); err == nil {
+		d.since = asOf.Add(-denylistOverlap).Format(time.RFC3339Nano)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// package response translates grpc errors into http responses
+
+package response
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorBody is the json envelope of every error returned by the gateway
+type ErrorBody struct {
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail describes a single field that failed validation
+type ErrorDetail struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// httpStatus maps grpc status codes to http status codes
+var httpStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// HTTPStatusFromCode returns the http status code for a grpc status code
+func HTTPStatusFromCode(code codes.Code) int {
+	if s, ok := httpStatus[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Error writes the error returned by a grpc call and aborts the request
+func Error(c *gin.Context, err error) {
+	st := status.Convert(err)
+	httpCode := HTTPStatusFromCode(st.Code())
+	body := ErrorBody{
+		Code:    codeName(st.Code()),
+		Message: st.Message(),
+		Details: fieldViolations(st),
+	}
+	// do not leak internal errors to the client
+	if httpCode >= http.StatusInternalServerError && st.Code() != codes.Unavailable && st.Code() != codes.Unimplemented {
+		body.Message = http.StatusText(httpCode)
+	}
+	if retryAfter, ok := retryDelay(st); ok {
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+	}
+	c.AbortWithStatusJSON(httpCode, body)
+}
+
+// BadRequest writes a 400 error for a request the gateway could not parse
+func BadRequest(c *gin.Context, message string) {
+	Error(c, status.Error(codes.InvalidArgument, message))
+}
+
+// Unauthorized writes a 401 error for a request without valid credentials
+func Unauthorized(c *gin.Context, message string) {
+	Error(c, status.Error(codes.Unauthenticated, message))
+}
+
+// Forbidden writes a 403 error for a caller without the required permission
+func Forbidden(c *gin.Context, message string) {
+	Error(c, status.Error(codes.PermissionDenied, message))
+}
+
+// codeName returns the grpc code in SCREAMING_SNAKE_CASE, e.g. NOT_FOUND
+func codeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(name[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+// fieldViolations collects the field violations attached to the status
+func fieldViolations(st *status.Status) []ErrorDetail {
+	var details []ErrorDetail
+	for _, d := range st.Details() {
+		switch detail := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range detail.GetFieldViolations() {
+				details = append(details, ErrorDetail{
+					Field:       v.GetField(),
+					Description: v.GetDescription(),
+				})
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range detail.GetViolations() {
+				details = append(details, ErrorDetail{
+					Field:       v.GetSubject(),
+					Description: v.GetDescription(),
+				})
+			}
+		}
+	}
+	return details
+}
+
+// retryDelay returns the seconds to wait before retrying, when the status carries them
+func retryDelay(st *status.Status) (int, bool) {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return int(math.Ceil(info.GetRetryDelay().AsDuration().Seconds())), true
+		}
+	}
+	return 0, false
+}
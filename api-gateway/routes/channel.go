@@ -0,0 +1,209 @@
+// routes for channel microservice
+
+package routes
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/middleware"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/channel/config"
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+	"google.golang.org/grpc"
+)
+
+// ChannelRoutes struct
+type ChannelRoutes struct {
+	channelClient pb.ChannelServiceClient
+	config        *config.Settings
+}
+
+// NewChannelRoutes returns a new channel routes
+func NewChannelRoutes(config *config.Settings) *ChannelRoutes {
+	client, err := grpc.Dial(fmt.Sprintf("%s:%s", config.Server.GrpcHost, config.Server.GrcpPort), grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(middleware.UnaryIdentityInterceptor()),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return &ChannelRoutes{
+		channelClient: pb.NewChannelServiceClient(client),
+		config:        config,
+	}
+}
+
+// RegisterChannelSvcRoutes registers the channel routes
+func (r *ChannelRoutes) RegisterChannelSvcRoutes(router *gin.Engine, authn *middleware.Authenticator) {
+	// public routes
+	public := router.Group("/api/v1", authn.Optional(auth.ScopeChannelsRead))
+	public.GET("/channels", r.ListChannels)
+	public.GET("/channels/:handle", r.GetChannel)
+
+	// routes that require an authenticated user
+	protected := router.Group("/api/v1", authn.Required(auth.ScopeChannelsWrite))
+	protected.POST("/channels", r.CreateChannel)
+	protected.PUT("/channels/:handle", r.UpdateChannel)
+	protected.DELETE("/channels/:handle", r.DeleteChannel)
+	protected.POST("/channels/:handle/subscription", r.Subscribe)
+	protected.DELETE("/channels/:handle/subscription", r.Unsubscribe)
+	reader := router.Group("/api/v1", authn.Required(auth.ScopeChannelsRead))
+	reader.GET("/channels/:handle/subscribers", r.ListSubscribers)
+	reader.GET("/subscriptions", r.ListSubscriptions)
+}
+
+// ListChannels returns the channels of the ownerId query parameter, the
+// caller's channels by default
+func (r *ChannelRoutes) ListChannels(c *gin.Context) {
+	ownerId := c.Query("ownerId")
+	if ownerId == "" {
+		if identity, ok := middleware.GetIdentity(c); ok {
+			ownerId = identity.UserID
+		}
+	}
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	channels, err := r.channelClient.ListChannels(c, &pb.ListChannelsRequest{
+		OwnerId: ownerId,
+		Page:    page,
+		Limit:   limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, channels)
+}
+
+// GetChannel returns a channel by handle
+func (r *ChannelRoutes) GetChannel(c *gin.Context) {
+	channel, err := r.channelClient.GetChannel(c, &pb.GetChannelRequest{
+		Handle: c.Param("handle"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, channel)
+}
+
+// CreateChannel creates a channel owned by the caller
+func (r *ChannelRoutes) CreateChannel(c *gin.Context) {
+	body := &pb.Channel{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	channel, err := r.channelClient.CreateChannel(c, &pb.CreateChannelRequest{Channel: body})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, channel)
+}
+
+// UpdateChannel updates a channel of the caller
+func (r *ChannelRoutes) UpdateChannel(c *gin.Context) {
+	body := &pb.Channel{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	channel, err := r.channelClient.UpdateChannel(c, &pb.UpdateChannelRequest{
+		Handle:  c.Param("handle"),
+		Channel: body,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, channel)
+}
+
+// DeleteChannel deletes a channel of the caller
+func (r *ChannelRoutes) DeleteChannel(c *gin.Context) {
+	_, err := r.channelClient.DeleteChannel(c, &pb.DeleteChannelRequest{
+		Handle: c.Param("handle"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Channel deleted successfully",
+	})
+}
+
+// Subscribe subscribes the caller to a channel
+func (r *ChannelRoutes) Subscribe(c *gin.Context) {
+	res, err := r.channelClient.Subscribe(c, &pb.SubscribeRequest{
+		Handle: c.Param("handle"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// Unsubscribe unsubscribes the caller from a channel
+func (r *ChannelRoutes) Unsubscribe(c *gin.Context) {
+	res, err := r.channelClient.Unsubscribe(c, &pb.UnsubscribeRequest{
+		Handle: c.Param("handle"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// ListSubscriptions returns the channels followed by the caller
+func (r *ChannelRoutes) ListSubscriptions(c *gin.Context) {
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	res, err := r.channelClient.ListSubscriptions(c, &pb.ListSubscriptionsRequest{
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// ListSubscribers returns the subscribers of a channel of the caller
+func (r *ChannelRoutes) ListSubscribers(c *gin.Context) {
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	res, err := r.channelClient.ListSubscribers(c, &pb.ListSubscribersRequest{
+		Handle: c.Param("handle"),
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
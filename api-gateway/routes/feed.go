@@ -0,0 +1,99 @@
+// routes for the feeds, combining the channel and the video microservices
+
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/middleware"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/common/auth"
+	channelpb "github.com/iamvasanth07/showcase/common/protos/channel"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/utils"
+)
+
+// subscriptionsPageSize is the page size used to collect the subscriptions of a feed
+const subscriptionsPageSize = 100
+
+// FeedRoutes struct
+type FeedRoutes struct {
+	channelClient channelpb.ChannelServiceClient
+	videoClient   pb.VideoServiceClient
+}
+
+// NewFeedRoutes returns a new feed routes sharing the clients of the channel and video routes
+func NewFeedRoutes(channels *ChannelRoutes, videos *VideoRoutes) *FeedRoutes {
+	return &FeedRoutes{
+		channelClient: channels.channelClient,
+		videoClient:   videos.videoClient,
+	}
+}
+
+// RegisterFeedRoutes registers the feed routes
+func (r *FeedRoutes) RegisterFeedRoutes(router *gin.Engine, authn *middleware.Authenticator) {
+	// routes that require an authenticated user
+	protected := router.Group("/api/v1", authn.Required(auth.ScopeChannelsRead))
+	protected.GET("/feed/subscriptions", r.GetSubscriptionsFeed)
+}
+
+// GetSubscriptionsFeed returns the newest public videos of the channels followed by the caller
+func (r *FeedRoutes) GetSubscriptionsFeed(c *gin.Context) {
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	channelIds, err := r.subscribedChannels(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if len(channelIds) == 0 {
+		c.JSON(200, &listVideosResponse{ListVideosResponse: &pb.ListVideosResponse{}})
+		return
+	}
+	videos, err := r.videoClient.GetVideoByChannelId(c, &pb.GetVideoByChannelIdRequest{
+		ChannelIds: channelIds,
+		Limit:      limit,
+		PageToken:  c.Query("pageToken"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	next := nextLink(c, videos.NextPageToken)
+	if next != "" {
+		c.Header("Link", "<"+next+">; rel=\"next\"")
+	}
+	c.JSON(200, &listVideosResponse{
+		ListVideosResponse: &pb.ListVideosResponse{
+			Videos:        videos.Videos,
+			NextPageToken: videos.NextPageToken,
+		},
+		Next: next,
+	})
+}
+
+// subscribedChannels returns the ids of the channels followed by the caller,
+// up to the channels a feed can list
+func (r *FeedRoutes) subscribedChannels(c *gin.Context) ([]string, error) {
+	var channelIds []string
+	for page := int32(1); len(channelIds) < utils.MaxFeedChannels; page++ {
+		res, err := r.channelClient.ListSubscriptions(c, &channelpb.ListSubscriptionsRequest{
+			Page:  page,
+			Limit: subscriptionsPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, channel := range res.Channels {
+			channelIds = append(channelIds, channel.Id)
+		}
+		if len(res.Channels) < subscriptionsPageSize {
+			break
+		}
+	}
+	if len(channelIds) > utils.MaxFeedChannels {
+		channelIds = channelIds[:utils.MaxFeedChannels]
+	}
+	return channelIds, nil
+}
@@ -0,0 +1,76 @@
+package routes
+
+// route publishing the public keys of the user service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/common/jwks"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// jwksTTL is how long the key set is cached by the gateway and by the clients
+const jwksTTL = 5 * time.Minute
+
+// JWKSRoutes struct
+type JWKSRoutes struct {
+	keys *jwks.Cache
+}
+
+// NewJWKSRoutes returns the key set routes and the cache verifying the tokens
+func NewJWKSRoutes(userClient pb.UserServiceClient) *JWKSRoutes {
+	return &JWKSRoutes{
+		keys: jwks.NewCache(fetchJWKS(userClient), jwksTTL),
+	}
+}
+
+// Keys returns the cache of the key set
+func (r *JWKSRoutes) Keys() *jwks.Cache {
+	return r.keys
+}
+
+// RegisterJWKSRoutes registers the key set route
+func (r *JWKSRoutes) RegisterJWKSRoutes(router *gin.Engine) {
+	router.GET("/.well-known/jwks.json", r.getJWKS)
+}
+
+// getJWKS returns the public keys verifying the access tokens
+func (r *JWKSRoutes) getJWKS(c *gin.Context) {
+	set, err := r.keys.Set(c)
+	if err != nil {
+		response.Error(c, status.Error(codes.Unavailable, err.Error()))
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksTTL.Seconds())))
+	c.JSON(200, set)
+}
+
+// fetchJWKS fetches the key set from the user service
+func fetchJWKS(client pb.UserServiceClient) jwks.Fetcher {
+	return func(ctx context.Context) (*jwks.Set, error) {
+		res, err := client.GetJWKS(ctx, &pb.GetJWKSRequest{})
+		if err != nil {
+			return nil, err
+		}
+		set := &jwks.Set{Keys: make([]jwks.Key, 0, len(res.Keys))}
+		for _, key := range res.Keys {
+			set.Keys = append(set.Keys, jwks.Key{
+				Kty: key.Kty,
+				Kid: key.Kid,
+				Use: key.Use,
+				Alg: key.Alg,
+				N:   key.N,
+				E:   key.E,
+				Crv: key.Crv,
+				X:   key.X,
+			})
+		}
+		return set, nil
+	}
+}
@@ -0,0 +1,84 @@
+// video playback through signed urls
+
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/playback"
+	"github.com/iamvasanth07/showcase/video/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPlaybackUrl returns the signed playback urls of a video
+func (r *VideoRoutes) GetPlaybackUrl(c *gin.Context) {
+	res, err := r.videoClient.GetPlaybackUrl(c, &pb.GetPlaybackUrlRequest{
+		Slug: c.Param("slug"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
+// Play streams a stored file of a video once the signature of the url is
+// verified. Range, If-None-Match and If-Modified-Since are handled by
+// http.ServeContent.
+func (r *VideoRoutes) Play(c *gin.Context) {
+	videoID := c.Param("id")
+	expires := c.Param("expires")
+	err := r.signer.Verify(videoID, expires, c.Param("signature"), time.Now())
+	if err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+	key, err := playback.ObjectKey(videoID, c.Param("file"))
+	if err != nil {
+		response.Error(c, status.Error(codes.NotFound, err.Error()))
+		return
+	}
+	obj, info, err := r.storage.Get(c, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		response.Error(c, status.Error(codes.NotFound, "file not found"))
+		return
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer obj.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	c.Header("ETag", fmt.Sprintf(`"%x-%x"`, info.LastModified.UnixNano(), info.Size))
+	c.Header("Accept-Ranges", "bytes")
+	// the url stops working at its expiry, caches must not outlive it
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge(expires)))
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.LastModified, obj)
+}
+
+// maxAge returns the seconds left until the unix timestamp expires
+func maxAge(expires string) int64 {
+	var unix int64
+	fmt.Sscan(expires, &unix)
+	left := time.Until(time.Unix(unix, 0)) / time.Second
+	if left < 0 {
+		return 0
+	}
+	return int64(left)
+}
@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/video/playback"
+	"github.com/iamvasanth07/showcase/video/storage"
+)
+
+const testSource = "0123456789abcdefghij"
+
+// newPlaybackRouter serves the playback routes of a video stored on disk
+func newPlaybackRouter(t *testing.T) (*gin.Engine, *VideoRoutes) {
+	t.Helper()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Put(context.Background(), "videos/v1/source.mp4", strings.NewReader(testSource), int64(len(testSource)), "video/mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &VideoRoutes{storage: store, signer: playback.NewSigner("secret", time.Minute)}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(playback.PathPrefix+"/:id/:expires/:signature/*file", r.Play)
+	router.HEAD(playback.PathPrefix+"/:id/:expires/:signature/*file", r.Play)
+	return router, r
+}
+
+func play(router *gin.Engine, method, url string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPlay(t *testing.T) {
+	router, r := newPlaybackRouter(t)
+	url, _ := r.signer.URL("v1", "source.mp4", time.Now())
+	etag := play(router, http.MethodGet, url, nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on the playback response")
+	}
+	expired, _ := r.signer.URL("v1", "source.mp4", time.Now().Add(-time.Hour))
+	missing, _ := r.signer.URL("v1", "hls/master.m3u8", time.Now())
+	outside, _ := r.signer.URL("v1", "uploads/part", time.Now())
+	signature := strings.Split(url, "/")[6]
+	tampered := strings.Replace(url, signature, strings.Repeat("A", len(signature)), 1)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		header       http.Header
+		want         int
+		body         string
+		contentRange string
+	}{
+		{"full file", http.MethodGet, url, nil, http.StatusOK, testSource, ""},
+		{"head", http.MethodHead, url, nil, http.StatusOK, "", ""},
+		{"range", http.MethodGet, url, http.Header{"Range": {"bytes=2-5"}}, http.StatusPartialContent, "2345", "bytes 2-5/20"},
+		{"suffix range", http.MethodGet, url, http.Header{"Range": {"bytes=-3"}}, http.StatusPartialContent, "hij", "bytes 17-19/20"},
+		{"unsatisfiable range", http.MethodGet, url, http.Header{"Range": {"bytes=50-60"}}, http.StatusRequestedRangeNotSatisfiable, "", "bytes */20"},
+		{"matching etag", http.MethodGet, url, http.Header{"If-None-Match": {etag}}, http.StatusNotModified, "", ""},
+		{"stale etag", http.MethodGet, url, http.Header{"If-None-Match": {`"stale"`}}, http.StatusOK, testSource, ""},
+		{"tampered signature", http.MethodGet, tampered, nil, http.StatusForbidden, "", ""},
+		{"other video", http.MethodGet, strings.Replace(url, "/play/v1/", "/play/v2/", 1), nil, http.StatusForbidden, "", ""},
+		{"expired url", http.MethodGet, expired, nil, http.StatusForbidden, "", ""},
+		{"missing file", http.MethodGet, missing, nil, http.StatusNotFound, "", ""},
+		{"file outside the video", http.MethodGet, outside, nil, http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := play(router, tt.method, tt.url, tt.header)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body, tt.body)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tt.contentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.contentRange)
+			}
+			if rec.Code == http.StatusOK && rec.Header().Get("Accept-Ranges") != "bytes" {
+				t.Error("ranges are not advertised")
+			}
+		})
+	}
+}
+
+func TestPlayCacheControl(t *testing.T) {
+	router, r := newPlaybackRouter(t)
+	url, _ := r.signer.URL("v1", "source.mp4", time.Now())
+	rec := play(router, http.MethodGet, url, nil)
+	got := rec.Header().Get("Cache-Control")
+	if got != "private, max-age=60" && got != "private, max-age=59" {
+		t.Errorf("Cache-Control = %q, want it bounded by the url expiry", got)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "video/mp4" {
+		t.Errorf("Content-Type = %q, want video/mp4", ct)
+	}
+}
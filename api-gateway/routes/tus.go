@@ -0,0 +1,184 @@
+// tus 1.0 resumable upload protocol, see https://tus.io/protocols/resumable-upload
+
+package routes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+)
+
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,expiration,termination"
+	tusContentType = "application/offset+octet-stream"
+	uploadsPath    = "/api/v1/uploads"
+)
+
+// tusResumable sets the Tus-Resumable header and rejects unsupported protocol versions
+func tusResumable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", tusVersion)
+		if c.Request.Method != http.MethodOptions && c.GetHeader("Tus-Resumable") != tusVersion {
+			c.Header("Tus-Version", tusVersion)
+			c.AbortWithStatus(http.StatusPreconditionFailed)
+			return
+		}
+		c.Next()
+	}
+}
+
+// TusOptions describes the supported tus versions and extensions
+func (r *VideoRoutes) TusOptions(c *gin.Context) {
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Status(http.StatusNoContent)
+}
+
+// CreateUpload starts a resumable upload
+func (r *VideoRoutes) CreateUpload(c *gin.Context) {
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		response.BadRequest(c, "invalid Upload-Length header")
+		return
+	}
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	title := metadata["title"]
+	if title == "" {
+		title = metadata["filename"]
+	}
+	res, err := r.videoClient.CreateUpload(c, &pb.CreateUploadRequest{
+		Length: length,
+		Metadata: &pb.UploadVideoMetadata{
+			Video: &pb.Video{
+				Title:       title,
+				Description: metadata["description"],
+				Category:    metadata["category"],
+				Language:    metadata["language"],
+				ChannelId:   metadata["channelId"],
+			},
+			FileName:    metadata["filename"],
+			ContentType: metadata["filetype"],
+			Size:        length,
+		},
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	setUploadHeaders(c, res.Upload)
+	c.Header("Location", fmt.Sprintf("%s/%s", uploadsPath, res.Upload.Id))
+	c.Status(http.StatusCreated)
+}
+
+// HeadUpload returns the offset of a resumable upload
+func (r *VideoRoutes) HeadUpload(c *gin.Context) {
+	res, err := r.videoClient.GetUpload(c, &pb.GetUploadRequest{Id: c.Param("id")})
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		response.Error(c, err)
+		return
+	}
+	setUploadHeaders(c, res.Upload)
+	c.Header("Upload-Length", strconv.FormatInt(res.Upload.Length, 10))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// PatchUpload appends the request body to a resumable upload
+func (r *VideoRoutes) PatchUpload(c *gin.Context) {
+	if c.ContentType() != tusContentType {
+		c.AbortWithStatus(http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.BadRequest(c, "invalid Upload-Offset header")
+		return
+	}
+	stream, err := r.videoClient.AppendUpload(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	err = stream.Send(&pb.AppendUploadRequest{
+		Data: &pb.AppendUploadRequest_Header{
+			Header: &pb.AppendUploadHeader{
+				Id:     c.Param("id"),
+				Offset: offset,
+			},
+		},
+	})
+	buf := make([]byte, uploadChunkSize)
+	for err == nil {
+		n, readErr := c.Request.Body.Read(buf)
+		if n > 0 {
+			err = stream.Send(&pb.AppendUploadRequest{
+				Data: &pb.AppendUploadRequest_Chunk{Chunk: buf[:n]},
+			})
+		}
+		if readErr != nil {
+			// on a dropped connection the bytes received so far are kept
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	setUploadHeaders(c, res.Upload)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUpload terminates a resumable upload
+func (r *VideoRoutes) DeleteUpload(c *gin.Context) {
+	_, err := r.videoClient.DeleteUpload(c, &pb.DeleteUploadRequest{Id: c.Param("id")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// setUploadHeaders writes the offset, expiry and created video of an upload
+func setUploadHeaders(c *gin.Context, upload *pb.Upload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	if expiresAt, err := time.Parse(time.RFC3339, upload.ExpiresAt); err == nil && upload.VideoId == "" {
+		c.Header("Upload-Expires", expiresAt.UTC().Format(http.TimeFormat))
+	}
+	if upload.VideoId != "" {
+		c.Header("Upload-Video-Id", upload.VideoId)
+	}
+}
+
+// parseUploadMetadata decodes the comma separated "key base64(value)" pairs of Upload-Metadata
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("invalid Upload-Metadata header")
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata value for %q", key)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
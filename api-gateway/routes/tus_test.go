@@ -0,0 +1,270 @@
+package routes
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUploads is an in-memory video service implementing the resumable upload calls
+type fakeUploads struct {
+	pb.VideoServiceClient
+
+	mu      sync.Mutex
+	uploads map[string]*pb.Upload
+	data    map[string][]byte
+	created *pb.CreateUploadRequest
+}
+
+func newFakeUploads() *fakeUploads {
+	return &fakeUploads{uploads: map[string]*pb.Upload{}, data: map[string][]byte{}}
+}
+
+func (f *fakeUploads) CreateUpload(ctx context.Context, in *pb.CreateUploadRequest, opts ...grpc.CallOption) (*pb.CreateUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = in
+	upload := &pb.Upload{
+		Id:        "upload-1",
+		Length:    in.Length,
+		ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+	}
+	f.uploads[upload.Id] = upload
+	return &pb.CreateUploadResponse{Upload: upload}, nil
+}
+
+func (f *fakeUploads) GetUpload(ctx context.Context, in *pb.GetUploadRequest, opts ...grpc.CallOption) (*pb.GetUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload, ok := f.uploads[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	return &pb.GetUploadResponse{Upload: upload}, nil
+}
+
+func (f *fakeUploads) DeleteUpload(ctx context.Context, in *pb.DeleteUploadRequest, opts ...grpc.CallOption) (*pb.DeleteUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.uploads[in.Id]; !ok {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	delete(f.uploads, in.Id)
+	return &pb.DeleteUploadResponse{Id: in.Id}, nil
+}
+
+func (f *fakeUploads) AppendUpload(ctx context.Context, opts ...grpc.CallOption) (pb.VideoService_AppendUploadClient, error) {
+	return &fakeAppendStream{uploads: f}, nil
+}
+
+// fakeAppendStream checks the offset of the header like the video service
+// and appends the chunks once the stream is closed
+type fakeAppendStream struct {
+	grpc.ClientStream
+
+	uploads *fakeUploads
+	upload  *pb.Upload
+	chunks  []byte
+	err     error
+}
+
+func (s *fakeAppendStream) Send(req *pb.AppendUploadRequest) error {
+	if s.err != nil {
+		return io.EOF
+	}
+	s.uploads.mu.Lock()
+	defer s.uploads.mu.Unlock()
+	switch data := req.Data.(type) {
+	case *pb.AppendUploadRequest_Header:
+		upload, ok := s.uploads.uploads[data.Header.Id]
+		switch {
+		case !ok:
+			s.err = status.Error(codes.NotFound, "upload not found")
+		case data.Header.Offset != upload.Offset:
+			s.err = status.Errorf(codes.Aborted, "offset mismatch, expected %d", upload.Offset)
+		}
+		s.upload = upload
+	case *pb.AppendUploadRequest_Chunk:
+		s.chunks = append(s.chunks, data.Chunk...)
+	}
+	return nil
+}
+
+func (s *fakeAppendStream) CloseAndRecv() (*pb.AppendUploadResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.uploads.mu.Lock()
+	defer s.uploads.mu.Unlock()
+	if s.upload.Offset+int64(len(s.chunks)) > s.upload.Length {
+		return nil, status.Error(codes.InvalidArgument, "chunks exceed the upload length")
+	}
+	s.uploads.data[s.upload.Id] = append(s.uploads.data[s.upload.Id], s.chunks...)
+	s.upload.Offset += int64(len(s.chunks))
+	if s.upload.Offset == s.upload.Length {
+		s.upload.VideoId = "video-1"
+	}
+	return &pb.AppendUploadResponse{Upload: s.upload}, nil
+}
+
+// newTusRouter serves the tus routes without authentication
+func newTusRouter(uploads *fakeUploads) *gin.Engine {
+	r := &VideoRoutes{videoClient: uploads}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.OPTIONS(uploadsPath, tusResumable(), r.TusOptions)
+	group := router.Group(uploadsPath, tusResumable())
+	group.POST("", r.CreateUpload)
+	group.HEAD("/:id", r.HeadUpload)
+	group.PATCH("/:id", r.PatchUpload)
+	group.DELETE("/:id", r.DeleteUpload)
+	return router
+}
+
+// tus sends a tus 1.0 request, header holds name and value pairs
+func tus(router *gin.Engine, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Tus-Resumable", tusVersion)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTusOptions(t *testing.T) {
+	router := newTusRouter(newFakeUploads())
+	req := httptest.NewRequest(http.MethodOptions, uploadsPath, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	for name, want := range map[string]string{
+		"Tus-Resumable": tusVersion,
+		"Tus-Version":   tusVersion,
+		"Tus-Extension": tusExtensions,
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestTusRejectsInvalidRequests(t *testing.T) {
+	uploads := newFakeUploads()
+	router := newTusRouter(uploads)
+	tus(router, http.MethodPost, uploadsPath, "", "Upload-Length", "10")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header []string
+		want   int
+	}{
+		{"unsupported version", http.MethodPost, uploadsPath, []string{"Tus-Resumable", "0.2.2", "Upload-Length", "10"}, http.StatusPreconditionFailed},
+		{"missing length", http.MethodPost, uploadsPath, nil, http.StatusBadRequest},
+		{"zero length", http.MethodPost, uploadsPath, []string{"Upload-Length", "0"}, http.StatusBadRequest},
+		{"invalid metadata", http.MethodPost, uploadsPath, []string{"Upload-Length", "10", "Upload-Metadata", "title !!"}, http.StatusBadRequest},
+		{"wrong content type", http.MethodPatch, uploadsPath + "/upload-1", []string{"Upload-Offset", "0", "Content-Type", "video/mp4"}, http.StatusUnsupportedMediaType},
+		{"missing offset", http.MethodPatch, uploadsPath + "/upload-1", []string{"Content-Type", tusContentType}, http.StatusBadRequest},
+		{"negative offset", http.MethodPatch, uploadsPath + "/upload-1", []string{"Upload-Offset", "-1", "Content-Type", tusContentType}, http.StatusBadRequest},
+		{"unknown upload", http.MethodHead, uploadsPath + "/upload-2", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := tus(router, tt.method, tt.path, "", tt.header...)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if got := rec.Header().Get("Tus-Resumable"); got != tusVersion {
+				t.Errorf("Tus-Resumable = %q, want %q", got, tusVersion)
+			}
+		})
+	}
+}
+
+func TestTusUpload(t *testing.T) {
+	uploads := newFakeUploads()
+	router := newTusRouter(uploads)
+	metadata := "filename " + base64.StdEncoding.EncodeToString([]byte("holiday.mp4")) +
+		",channelId " + base64.StdEncoding.EncodeToString([]byte("channel-1"))
+
+	rec := tus(router, http.MethodPost, uploadsPath, "", "Upload-Length", "10", "Upload-Metadata", metadata)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	location := rec.Header().Get("Location")
+	if location != uploadsPath+"/upload-1" {
+		t.Fatalf("Location = %q", location)
+	}
+	if rec.Header().Get("Upload-Offset") != "0" || rec.Header().Get("Upload-Expires") == "" {
+		t.Errorf("create: Upload-Offset %q, Upload-Expires %q", rec.Header().Get("Upload-Offset"), rec.Header().Get("Upload-Expires"))
+	}
+	video := uploads.created.Metadata.Video
+	if video.Title != "holiday.mp4" || video.ChannelId != "channel-1" || uploads.created.Length != 10 {
+		t.Errorf("create request = %v, want the metadata of the upload", uploads.created)
+	}
+
+	steps := []struct {
+		name       string
+		offset     string
+		body       string
+		want       int
+		wantOffset string
+	}{
+		{"first chunk", "0", "0123", http.StatusNoContent, "4"},
+		{"stale offset", "0", "0123", http.StatusConflict, "4"},
+		{"offset ahead", "6", "6789", http.StatusConflict, "4"},
+		{"second chunk", "4", "45", http.StatusNoContent, "6"},
+		{"last chunk", "6", "6789", http.StatusNoContent, "10"},
+	}
+	for _, step := range steps {
+		rec := tus(router, http.MethodPatch, location, step.body, "Upload-Offset", step.offset, "Content-Type", tusContentType)
+		if rec.Code != step.want {
+			t.Fatalf("%s: status = %d, want %d: %s", step.name, rec.Code, step.want, rec.Body)
+		}
+		head := tus(router, http.MethodHead, location, "")
+		if head.Code != http.StatusOK {
+			t.Fatalf("%s: head status = %d, want 200", step.name, head.Code)
+		}
+		if got := head.Header().Get("Upload-Offset"); got != step.wantOffset {
+			t.Errorf("%s: Upload-Offset = %q, want %q", step.name, got, step.wantOffset)
+		}
+		if head.Header().Get("Upload-Length") != "10" || head.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("%s: head Upload-Length %q, Cache-Control %q", step.name, head.Header().Get("Upload-Length"), head.Header().Get("Cache-Control"))
+		}
+	}
+
+	if got := string(uploads.data["upload-1"]); got != "0123456789" {
+		t.Errorf("stored data = %q, want 0123456789", got)
+	}
+	rec = tus(router, http.MethodHead, location, "")
+	if got := rec.Header().Get("Upload-Video-Id"); got != "video-1" {
+		t.Errorf("Upload-Video-Id = %q, want video-1", got)
+	}
+	if got := rec.Header().Get("Upload-Expires"); got != "" {
+		t.Errorf("Upload-Expires = %q on a complete upload", got)
+	}
+
+	if rec := tus(router, http.MethodDelete, location, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status = %d, want 204", rec.Code)
+	}
+	if rec := tus(router, http.MethodHead, location, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("head after delete: status = %d, want 404", rec.Code)
+	}
+}
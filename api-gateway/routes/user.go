@@ -3,9 +3,14 @@ package routes
 // routes for the user microservice
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/middleware"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/common/auth"
 	pb "github.com/iamvasanth07/showcase/common/protos/user"
 	"github.com/iamvasanth07/showcase/user/config"
 	"google.golang.org/grpc"
@@ -16,6 +21,42 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type SecondFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type CreateTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int32    `json:"expires_in_days"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
 type UserCreateRequest struct {
 	Email     string `json:"email"`
 	Password  string `json:"password"`
@@ -34,6 +75,13 @@ type UserUpdateRequest struct {
 	Phone     string `json:"phone"`
 }
 
+// oidcStateCookie binds the state of an external login to the browser that
+// started it, the callback is rejected without it
+const oidcStateCookie = "oidc_state"
+
+// oidcStateMaxAge matches the lifetime of the login states in seconds
+const oidcStateMaxAge = 600
+
 // UserRoutes struct
 type UserRoutes struct {
 	userClient pb.UserServiceClient
@@ -43,7 +91,10 @@ type UserRoutes struct {
 // NewUserRoutes returns a new user routes
 func NewUserRoutes(config *config.Settings) *UserRoutes {
 
-	client, err := grpc.Dial(fmt.Sprintf("%s:%s", config.Server.GrpcHost, config.Server.GrcpPort), grpc.WithInsecure())
+	client, err := grpc.Dial(fmt.Sprintf("%s:%s", config.Server.GrpcHost, config.Server.GrcpPort), grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(middleware.UnaryIdentityInterceptor()),
+		grpc.WithChainStreamInterceptor(middleware.StreamIdentityInterceptor()),
+	)
 	if err != nil {
 		panic(err)
 	}
@@ -54,15 +105,53 @@ func NewUserRoutes(config *config.Settings) *UserRoutes {
 }
 
 // RegisterRoutes registers the user routes
-func (r *UserRoutes) RegisterUserSvcRoutes(router *gin.Engine) {
+func (r *UserRoutes) RegisterUserSvcRoutes(router *gin.Engine, authn *middleware.Authenticator) {
+
+	// public routes
+	public := router.Group("/api/v1", authn.Optional())
+	public.GET("/user/:id", r.getUser)
+	public.POST("/user", r.createUser)
+
+	// routes that authenticate with the request body, they ignore the
+	// Authorization header so that an expired access token sent by habit
+	// does not keep a client from logging in or refreshing it
+	credentials := router.Group("/api/v1")
+	credentials.POST("/user/login", r.login)
+	credentials.POST("/user/login/second-factor", r.verifySecondFactor)
+	credentials.GET("/user/oidc/:provider/login", r.startOIDCLogin)
+	credentials.GET("/user/oidc/:provider/callback", r.completeOIDCLogin)
+	credentials.POST("/user/token/refresh", r.refresh)
+	credentials.POST("/user/password/forgot", r.forgotPassword)
+	credentials.POST("/user/password/reset", r.resetPassword)
+	credentials.POST("/user/email/verify", r.verifyEmail)
+
+	// routes that require an authenticated user
+	protected := router.Group("/api/v1", authn.Required())
+	protected.PUT("/user/:id", r.updateUser)
+	protected.DELETE("/user/:id", r.deleteUser)
+	protected.POST("/user/logout", r.logout)
+	protected.POST("/user/email/verification", r.resendVerification)
+	protected.POST("/user/2fa/totp", r.enrollTOTP)
+	protected.POST("/user/2fa/totp/confirm", r.confirmTOTP)
+	protected.DELETE("/user/2fa/totp", r.disableTOTP)
+	protected.POST("/user/tokens", r.createToken)
+	protected.GET("/user/tokens", r.listTokens)
+	protected.DELETE("/user/tokens/:id", r.revokeToken)
+	protected.GET("/user/sessions", r.listSessions)
+	protected.DELETE("/user/sessions/others", r.revokeOtherSessions)
+	protected.DELETE("/user/sessions/:id", r.revokeSession)
 
-	routes := router.Group("/api/v1")
+	// role management, restricted to the admins
+	admin := router.Group("/api/v1", authn.Required(), middleware.RequirePermission(auth.PermRoleManage))
+	admin.GET("/users", r.listUsers)
+	admin.GET("/roles", r.listRoles)
+	admin.GET("/user/:id/roles", r.listRoles)
+	admin.PUT("/user/:id/roles/:role", r.assignRole)
+	admin.DELETE("/user/:id/roles/:role", r.revokeRole)
 
-	routes.GET("/user/:id", r.getUser)
-	routes.POST("/user", r.createUser)
-	routes.PUT("/user/:id", r.updateUser)
-	routes.DELETE("/user/:id", r.deleteUser)
-	routes.POST("/user/login", r.login)
+	// lockout management
+	unlock := router.Group("/api/v1", authn.Required(), middleware.RequirePermission(auth.PermUserUnlock))
+	unlock.POST("/user/:id/unlock", r.unlockUser)
 }
 
 // getUser call the user grpc service and returns a user
@@ -70,9 +159,7 @@ func (r *UserRoutes) getUser(c *gin.Context) {
 	id := c.Param("id")
 	user, err := r.userClient.Get(c, &pb.GetUserRequest{Id: id})
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": "error",
-		})
+		response.Error(c, err)
 		return
 	}
 	c.JSON(200, gin.H{
@@ -80,13 +167,46 @@ func (r *UserRoutes) getUser(c *gin.Context) {
 	})
 }
 
+// listUsers returns a page of the users, the next page is linked with the
+// pageToken of the service
+func (r *UserRoutes) listUsers(c *gin.Context) {
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	users, err := r.userClient.GetAll(c, &pb.GetAllUserRequest{
+		Paginate: &pb.Pagination{
+			Page:      page,
+			Limit:     limit,
+			PageToken: c.Query("pageToken"),
+		},
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	next := nextLink(c, users.NextPageToken)
+	if next != "" {
+		c.Header("Link", "<"+next+">; rel=\"next\"")
+	}
+	c.JSON(200, &listUsersResponse{GetAllUserResponse: users, Next: next})
+}
+
+// listUsersResponse is a page of users with the link to the next one
+type listUsersResponse struct {
+	*pb.GetAllUserResponse
+	Next string `json:"next,omitempty"`
+}
+
 // createUser creates a user
 func (r *UserRoutes) createUser(c *gin.Context) {
 	body := &UserCreateRequest{}
 	if err := c.ShouldBindJSON(body); err != nil {
-		c.JSON(400, gin.H{
-			"message": "Bad Request",
-		})
+		response.BadRequest(c, err.Error())
 		return
 	}
 	req := &pb.CreateUserRequest{
@@ -101,10 +221,7 @@ func (r *UserRoutes) createUser(c *gin.Context) {
 	}
 	res, err := r.userClient.Create(c, req)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": "Internal Server Error",
-			"error":   err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 	c.JSON(200, gin.H{
@@ -116,12 +233,11 @@ func (r *UserRoutes) createUser(c *gin.Context) {
 func (r *UserRoutes) updateUser(c *gin.Context) {
 	body := &UserUpdateRequest{}
 	if err := c.ShouldBindJSON(body); err != nil {
-		c.JSON(400, gin.H{
-			"message": "Bad Request",
-		})
+		response.BadRequest(c, err.Error())
 		return
 	}
 	req := &pb.UpdateUserRequest{
+		Id: c.Param("id"),
 		User: &pb.User{
 			Email:     body.Email,
 			Password:  body.Password,
@@ -133,9 +249,7 @@ func (r *UserRoutes) updateUser(c *gin.Context) {
 	}
 	res, err := r.userClient.Update(c, req)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": "Internal Server Error",
-		})
+		response.Error(c, err)
 		return
 	}
 	c.JSON(200, gin.H{
@@ -148,9 +262,7 @@ func (r *UserRoutes) deleteUser(c *gin.Context) {
 	id := c.Param("id")
 	res, err := r.userClient.Delete(c, &pb.DeleteUserRequest{Id: id})
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": "Internal Server Error",
-		})
+		response.Error(c, err)
 		return
 	}
 	c.JSON(200, gin.H{
@@ -162,9 +274,7 @@ func (r *UserRoutes) deleteUser(c *gin.Context) {
 func (r *UserRoutes) login(c *gin.Context) {
 	body := &LoginRequest{}
 	if err := c.ShouldBindJSON(body); err != nil {
-		c.JSON(400, gin.H{
-			"message": "Bad Request",
-		})
+		response.BadRequest(c, err.Error())
 		return
 	}
 	req := &pb.LoginRequest{
@@ -173,12 +283,387 @@ func (r *UserRoutes) login(c *gin.Context) {
 	}
 	res, err := r.userClient.Login(c, req)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": "Internal Server Error",
+		response.Error(c, err)
+		return
+	}
+	loginResponse(c, res)
+}
+
+// verifySecondFactor completes a login with the challenge token and a code
+func (r *UserRoutes) verifySecondFactor(c *gin.Context) {
+	body := &SecondFactorRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.userClient.VerifySecondFactor(c, &pb.VerifySecondFactorRequest{
+		ChallengeToken: body.ChallengeToken,
+		Code:           body.Code,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loginResponse(c, res)
+}
+
+// startOIDCLogin sends the user to an external provider, the state of the
+// login is kept in a cookie scoped to the callback
+func (r *UserRoutes) startOIDCLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	res, err := r.userClient.StartOIDCLogin(c, &pb.StartOIDCLoginRequest{
+		Provider:  provider,
+		LoginHint: c.Query("login_hint"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// lax so that the cookie comes along with the redirect of the provider
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, res.State, oidcStateMaxAge, oidcCallbackPath(provider), "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, res.AuthorizationUrl)
+}
+
+// completeOIDCLogin handles the redirect of an external provider, the state
+// must be the one of the cookie so that a login cannot be completed in
+// another browser
+func (r *UserRoutes) completeOIDCLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	state, _ := c.Cookie(oidcStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, oidcCallbackPath(provider), "", c.Request.TLS != nil, true)
+	if providerErr := c.Query("error"); providerErr != "" {
+		response.BadRequest(c, fmt.Sprintf("the provider refused the login: %s %s", providerErr, c.Query("error_description")))
+		return
+	}
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		response.BadRequest(c, "the login state does not match, start the login again")
+		return
+	}
+	res, err := r.userClient.CompleteOIDCLogin(c, &pb.CompleteOIDCLoginRequest{
+		Provider: provider,
+		Code:     c.Query("code"),
+		State:    state,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loginResponse(c, res)
+}
+
+// oidcCallbackPath returns the path of the callback of a provider
+func oidcCallbackPath(provider string) string {
+	return "/api/v1/user/oidc/" + provider + "/callback"
+}
+
+// loginResponse writes the tokens of a login, or the challenge of its
+// second step
+func loginResponse(c *gin.Context, res *pb.LoginResponse) {
+	if res.ChallengeToken != "" {
+		c.JSON(200, gin.H{
+			"second_factor_required": true,
+			"challenge_token":        res.ChallengeToken,
 		})
 		return
 	}
 	c.JSON(200, gin.H{
-		"token": res.Token,
+		"token":         res.Token,
+		"refresh_token": res.RefreshToken,
+		"expires_in":    res.ExpiresIn,
+	})
+}
+
+// refresh exchanges a refresh token for new tokens
+func (r *UserRoutes) refresh(c *gin.Context) {
+	body := &RefreshRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.userClient.Refresh(c, &pb.RefreshRequest{RefreshToken: body.RefreshToken})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"token":         res.Token,
+		"refresh_token": res.RefreshToken,
+		"expires_in":    res.ExpiresIn,
+	})
+}
+
+// logout revokes the access token of the request and the refresh token of the body
+func (r *UserRoutes) logout(c *gin.Context) {
+	body := &LogoutRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(body); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+	if _, err := r.userClient.Logout(c, &pb.LogoutRequest{RefreshToken: body.RefreshToken}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// forgotPassword emails a reset token, the response is the same for unknown emails
+func (r *UserRoutes) forgotPassword(c *gin.Context) {
+	body := &ForgotPasswordRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if _, err := r.userClient.RequestPasswordReset(c, &pb.RequestPasswordResetRequest{Email: body.Email}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(202, gin.H{
+		"message": "If an account uses this email, a reset link has been sent to it",
+	})
+}
+
+// resetPassword replaces the password with an emailed reset token
+func (r *UserRoutes) resetPassword(c *gin.Context) {
+	body := &ResetPasswordRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	_, err := r.userClient.ResetPassword(c, &pb.ResetPasswordRequest{
+		Token:    body.Token,
+		Password: body.Password,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Password reset successfully",
+	})
+}
+
+// verifyEmail verifies the email address of an emailed verification token
+func (r *UserRoutes) verifyEmail(c *gin.Context) {
+	body := &VerifyEmailRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.userClient.VerifyEmail(c, &pb.VerifyEmailRequest{Token: body.Token})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Email address verified, refresh the access token to use it",
+		"user":    res.User,
+	})
+}
+
+// resendVerification emails a new verification link to the caller
+func (r *UserRoutes) resendVerification(c *gin.Context) {
+	if _, err := r.userClient.ResendVerification(c, &pb.ResendVerificationRequest{}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(202, gin.H{
+		"message": "A verification link has been sent to your email address",
+	})
+}
+
+// enrollTOTP returns the secret of a new authenticator app of the caller
+func (r *UserRoutes) enrollTOTP(c *gin.Context) {
+	res, err := r.userClient.EnrollTOTP(c, &pb.EnrollTOTPRequest{})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"secret": res.Secret,
+		"uri":    res.Uri,
+	})
+}
+
+// confirmTOTP enables the enrolled authenticator app and returns the recovery codes
+func (r *UserRoutes) confirmTOTP(c *gin.Context) {
+	body := &TOTPCodeRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.userClient.ConfirmTOTP(c, &pb.ConfirmTOTPRequest{Code: body.Code})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message":        "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		"recovery_codes": res.RecoveryCodes,
+	})
+}
+
+// disableTOTP disables two-factor authentication with a code
+func (r *UserRoutes) disableTOTP(c *gin.Context) {
+	body := &TOTPCodeRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if _, err := r.userClient.DisableTOTP(c, &pb.DisableTOTPRequest{Code: body.Code}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// unlockUser lifts the lockout of the user of the path
+func (r *UserRoutes) unlockUser(c *gin.Context) {
+	res, err := r.userClient.UnlockUser(c, &pb.UnlockUserRequest{UserId: c.Param("id")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "User unlocked",
+		"user":    res.User,
+	})
+}
+
+// createToken creates a personal access token of the caller
+func (r *UserRoutes) createToken(c *gin.Context) {
+	body := &CreateTokenRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.userClient.CreatePersonalAccessToken(c, &pb.CreatePersonalAccessTokenRequest{
+		Name:          body.Name,
+		Scopes:        body.Scopes,
+		ExpiresInDays: body.ExpiresInDays,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(201, gin.H{
+		"message":               "Copy the token now, it will not be shown again",
+		"token":                 res.Token,
+		"personal_access_token": res.PersonalAccessToken,
+	})
+}
+
+// listTokens returns the personal access tokens of the caller
+func (r *UserRoutes) listTokens(c *gin.Context) {
+	res, err := r.userClient.ListPersonalAccessTokens(c, &pb.ListPersonalAccessTokensRequest{})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"personal_access_tokens": res.PersonalAccessTokens,
+	})
+}
+
+// revokeToken revokes a personal access token of the caller
+func (r *UserRoutes) revokeToken(c *gin.Context) {
+	if _, err := r.userClient.RevokePersonalAccessToken(c, &pb.RevokePersonalAccessTokenRequest{Id: c.Param("id")}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Personal access token revoked",
+	})
+}
+
+// listSessions returns the devices the caller is signed in from
+func (r *UserRoutes) listSessions(c *gin.Context) {
+	res, err := r.userClient.ListSessions(c, &pb.ListSessionsRequest{})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"sessions": res.Sessions,
+	})
+}
+
+// revokeSession signs out a session of the caller
+func (r *UserRoutes) revokeSession(c *gin.Context) {
+	if _, err := r.userClient.RevokeSession(c, &pb.RevokeSessionRequest{Id: c.Param("id")}); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Session revoked",
+	})
+}
+
+// revokeOtherSessions signs out every session of the caller but the current one
+func (r *UserRoutes) revokeOtherSessions(c *gin.Context) {
+	res, err := r.userClient.RevokeAllOtherSessions(c, &pb.RevokeAllOtherSessionsRequest{})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Other sessions revoked",
+		"revoked": res.Revoked,
+	})
+}
+
+// Client returns the client of the user grpc service
+func (r *UserRoutes) Client() pb.UserServiceClient {
+	return r.userClient
+}
+
+// listRoles returns every role, or the roles of the user of the path
+func (r *UserRoutes) listRoles(c *gin.Context) {
+	res, err := r.userClient.ListRoles(c, &pb.ListRolesRequest{UserId: c.Param("id")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"roles": res.Roles,
+	})
+}
+
+// assignRole grants a role to a user
+func (r *UserRoutes) assignRole(c *gin.Context) {
+	res, err := r.userClient.AssignRole(c, &pb.AssignRoleRequest{
+		UserId: c.Param("id"),
+		Role:   c.Param("role"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"userId": res.UserId,
+		"roles":  res.Roles,
+	})
+}
+
+// revokeRole takes a role back from a user
+func (r *UserRoutes) revokeRole(c *gin.Context) {
+	res, err := r.userClient.RevokeRole(c, &pb.RevokeRoleRequest{
+		UserId: c.Param("id"),
+		Role:   c.Param("role"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"userId": res.UserId,
+		"roles":  res.Roles,
 	})
 }
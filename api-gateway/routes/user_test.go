@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUsers answers the user listings with a next page token until the
+// pageToken of the request is "last"
+type fakeUsers struct {
+	pb.UserServiceClient
+	request *pb.GetAllUserRequest
+}
+
+func (f *fakeUsers) GetAll(ctx context.Context, in *pb.GetAllUserRequest, opts ...grpc.CallOption) (*pb.GetAllUserResponse, error) {
+	f.request = in
+	if in.Paginate.PageToken == "tampered" {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	res := &pb.GetAllUserResponse{Users: []*pb.User{{Id: "user-1"}}}
+	if in.Paginate.PageToken != "last" {
+		res.NextPageToken = "last"
+	}
+	return res, nil
+}
+
+func TestListUsers(t *testing.T) {
+	users := &fakeUsers{}
+	r := &UserRoutes{userClient: users}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/users", r.listUsers)
+
+	tests := []struct {
+		name      string
+		url       string
+		want      int
+		wantNext  string
+		wantLimit int32
+	}{
+		{"first page", "/api/v1/users?limit=10", http.StatusOK, "/api/v1/users?limit=10&pageToken=last", 10},
+		{"offset page", "/api/v1/users?page=2&limit=10", http.StatusOK, "/api/v1/users?limit=10&pageToken=last", 10},
+		{"last page", "/api/v1/users?limit=10&pageToken=last", http.StatusOK, "", 10},
+		{"tampered token", "/api/v1/users?pageToken=tampered", http.StatusBadRequest, "", 0},
+		{"malformed limit", "/api/v1/users?limit=ten", http.StatusBadRequest, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users.request = nil
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			if users.request.Paginate.Limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", users.request.Paginate.Limit, tt.wantLimit)
+			}
+			var body struct {
+				Next string `json:"next"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Next != tt.wantNext {
+				t.Errorf("next = %q, want %q", body.Next, tt.wantNext)
+			}
+			wantLink := ""
+			if tt.wantNext != "" {
+				wantLink = "<" + tt.wantNext + ">; rel=\"next\""
+			}
+			if got := rec.Header().Get("Link"); got != wantLink {
+				t.Errorf("Link = %q, want %q", got, wantLink)
+			}
+		})
+	}
+}
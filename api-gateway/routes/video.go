@@ -3,59 +3,188 @@
 package routes
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamvasanth07/showcase/api-gateway/middleware"
+	"github.com/iamvasanth07/showcase/api-gateway/response"
+	"github.com/iamvasanth07/showcase/common/auth"
 	pb "github.com/iamvasanth07/showcase/common/protos/video"
 	"github.com/iamvasanth07/showcase/video/config"
+	"github.com/iamvasanth07/showcase/video/playback"
+	"github.com/iamvasanth07/showcase/video/storage"
 	"google.golang.org/grpc"
 )
 
 // VideoRoutes struct
 type VideoRoutes struct {
 	videoClient pb.VideoServiceClient
+	storage     storage.Storage
+	signer      *playback.Signer
 	config      *config.Settings
 }
 
 // NewVideoRoutes returns a new video routes
 func NewVideoRoutes(config *config.Settings) *VideoRoutes {
 	log.Println(config.Server)
-	client, err := grpc.Dial(fmt.Sprintf("%s:%s", config.Server.GrpcHost, config.Server.GrcpPort), grpc.WithInsecure())
+	client, err := grpc.Dial(fmt.Sprintf("%s:%s", config.Server.GrpcHost, config.Server.GrcpPort), grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(middleware.UnaryIdentityInterceptor()),
+		grpc.WithChainStreamInterceptor(middleware.StreamIdentityInterceptor()),
+	)
+	if err != nil {
+		panic(err)
+	}
+	store, err := storage.New(config)
 	if err != nil {
 		panic(err)
 	}
 	return &VideoRoutes{
 		config:      config,
 		videoClient: pb.NewVideoServiceClient(client),
+		storage:     store,
+		signer:      playback.NewSigner(config.Playback.Secret, time.Duration(config.Playback.TTL)*time.Minute),
 	}
 }
 
 // RegisterRoutes registers the video routes
-func (r *VideoRoutes) RegisterVideoSvcRoutes(router *gin.Engine) {
-	routes := router.Group("/api/v1")
-	routes.GET("/videos", r.GetVideos)
-	routes.GET("/videos/:slug", r.GetVideo)
-	routes.POST("/videos", r.CreateVideo)
-	routes.PUT("/videos/:slug", r.UpdateVideo)
-	routes.DELETE("/videos/:slug", r.DeleteVideo)
+func (r *VideoRoutes) RegisterVideoSvcRoutes(router *gin.Engine, authn *middleware.Authenticator) {
+	// public routes
+	public := router.Group("/api/v1", authn.Optional(auth.ScopeVideosRead))
+	public.GET("/videos", r.GetVideos)
+	public.GET("/videos/search", r.SearchVideos)
+	public.GET("/videos/:slug", r.GetVideo)
+	public.GET("/videos/:slug/processing", r.GetProcessingStatus)
+	public.GET("/videos/:slug/playback", r.GetPlaybackUrl)
+
+	// signed playback urls, verified by the gateway itself
+	router.GET(playback.PathPrefix+"/:id/:expires/:signature/*file", r.Play)
+	router.HEAD(playback.PathPrefix+"/:id/:expires/:signature/*file", r.Play)
+
+	// routes that require an authenticated user
+	protected := router.Group("/api/v1", authn.Required(auth.ScopeVideosWrite))
+	protected.POST("/videos", r.CreateVideo)
+	protected.POST("/videos/upload", r.UploadVideo)
+	protected.POST("/videos/:slug/thumbnail", r.UploadThumbnail)
+	protected.PUT("/videos/:slug", r.UpdateVideo)
+	protected.DELETE("/videos/:slug", r.DeleteVideo)
+
+	// tus resumable uploads
+	router.OPTIONS(uploadsPath, tusResumable(), r.TusOptions)
+	uploads := router.Group(uploadsPath, tusResumable(), authn.Required(auth.ScopeVideosWrite))
+	uploads.POST("", r.CreateUpload)
+	uploads.HEAD("/:id", r.HeadUpload)
+	uploads.PATCH("/:id", r.PatchUpload)
+	uploads.DELETE("/:id", r.DeleteUpload)
 }
 
-// GetVideos returns all the videos
+// GetVideos returns the videos matching the filters of the query parameters
 func (r *VideoRoutes) GetVideos(c *gin.Context) {
-	page, _ := strconv.Atoi(c.Query("page"))
-	limit, _ := strconv.Atoi(c.Query("limit"))
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	var tags []string
+	for _, value := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
 	videos, err := r.videoClient.ListVideos(c, &pb.ListVideosRequest{
-		Page:  int32(page),
-		Limit: int32(limit),
+		Page:            page,
+		Limit:           limit,
+		Category:        c.Query("category"),
+		Language:        c.Query("language"),
+		ChannelId:       c.Query("channelId"),
+		Tags:            tags,
+		TagMatch:        c.Query("tagMatch"),
+		Privacy:         c.Query("privacy"),
+		PublishedAfter:  c.Query("publishedAfter"),
+		PublishedBefore: c.Query("publishedBefore"),
+		Sort:            c.Query("sort"),
+		PageToken:       c.Query("pageToken"),
 	})
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+		response.Error(c, err)
+		return
 	}
-	c.JSON(200, videos)
+	next := nextLink(c, videos.NextPageToken)
+	if next != "" {
+		c.Header("Link", "<"+next+">; rel=\"next\"")
+	}
+	c.JSON(200, &listVideosResponse{ListVideosResponse: videos, Next: next})
+}
+
+// listVideosResponse is a page of videos with the link to the next one
+type listVideosResponse struct {
+	*pb.ListVideosResponse
+	Next string `json:"next,omitempty"`
+}
+
+// nextLink returns the url of the page following the current request, empty
+// on the last page
+func nextLink(c *gin.Context, token string) string {
+	if token == "" {
+		return ""
+	}
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Set("pageToken", token)
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// queryInt parses an optional integer query parameter, answering 400 when it is malformed
+func queryInt(c *gin.Context, name string) (int32, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		response.BadRequest(c, name+" must be an integer")
+		return 0, false
+	}
+	return int32(n), true
+}
+
+// SearchVideos returns the videos matching the q query parameter
+func (r *VideoRoutes) SearchVideos(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		response.BadRequest(c, "q is required")
+		return
+	}
+	page, ok := queryInt(c, "page")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+	results, err := r.videoClient.SearchVideos(c, &pb.SearchVideosRequest{
+		Query:    query,
+		Language: c.Query("language"),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, results)
 }
 
 // GetVideo returns a video
@@ -65,26 +194,35 @@ func (r *VideoRoutes) GetVideo(c *gin.Context) {
 		Slug: slug,
 	})
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+		response.Error(c, err)
+		return
 	}
 	c.JSON(200, video)
 }
 
+// GetProcessingStatus returns the transcoding progress of a video
+func (r *VideoRoutes) GetProcessingStatus(c *gin.Context) {
+	res, err := r.videoClient.GetProcessingStatus(c, &pb.GetProcessingStatusRequest{
+		Slug: c.Param("slug"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
+
 // CreateVideo creates a video
 func (r *VideoRoutes) CreateVideo(c *gin.Context) {
 	body := &pb.CreateVideoRequest{}
-	if err := c.BindJSON(body); err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
 	video, err := r.videoClient.CreateVideo(c, body)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+		response.Error(c, err)
+		return
 	}
 	c.JSON(200, video)
 
@@ -92,22 +230,19 @@ func (r *VideoRoutes) CreateVideo(c *gin.Context) {
 
 // UpdateVideo updates a video
 func (r *VideoRoutes) UpdateVideo(c *gin.Context) {
-	slug := c.Param("slug")
-	body := &pb.UpdateVideoRequest{
-		Video: &pb.Video{
-			Slug: slug,
-		},
+	body := &pb.UpdateVideoRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		response.BadRequest(c, err.Error())
+		return
 	}
-	if err := c.BindJSON(body); err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+	if body.Video == nil {
+		body.Video = &pb.Video{}
 	}
+	body.Video.Slug = c.Param("slug")
 	video, err := r.videoClient.UpdateVideo(c, body)
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+		response.Error(c, err)
+		return
 	}
 	c.JSON(200, video)
 }
@@ -119,11 +254,146 @@ func (r *VideoRoutes) DeleteVideo(c *gin.Context) {
 		Slug: slug,
 	})
 	if err != nil {
-		c.JSON(500, gin.H{
-			"message": err.Error(),
-		})
+		response.Error(c, err)
+		return
 	}
 	c.JSON(200, gin.H{
 		"message": "Video deleted successfully",
 	})
 }
+
+// uploadChunkSize is the size of the chunks streamed to the video service
+const uploadChunkSize = 1 << 20
+
+// UploadVideo streams a multipart/form-data upload to the video service. The
+// form fields must precede the "file" part.
+func (r *VideoRoutes) UploadVideo(c *gin.Context) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		response.BadRequest(c, "expected a multipart/form-data body")
+		return
+	}
+	video := &pb.Video{}
+	fields := map[string]*string{
+		"title":       &video.Title,
+		"description": &video.Description,
+		"category":    &video.Category,
+		"language":    &video.Language,
+		"channelId":   &video.ChannelId,
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			response.BadRequest(c, "file is required")
+			return
+		}
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		if part.FormName() != "file" {
+			if field, ok := fields[part.FormName()]; ok {
+				value, err := io.ReadAll(io.LimitReader(part, 64<<10))
+				if err != nil {
+					response.BadRequest(c, err.Error())
+					return
+				}
+				*field = string(value)
+			}
+			continue
+		}
+		r.streamUpload(c, video, part)
+		return
+	}
+}
+
+// streamUpload sends the metadata and the content of the file part to the video service
+func (r *VideoRoutes) streamUpload(c *gin.Context, video *pb.Video, part *multipart.Part) {
+	// cancelling the context aborts the stream, the video service then discards
+	// the part of the file it received
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+	stream, err := r.videoClient.UploadVideo(ctx)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	err = stream.Send(&pb.UploadVideoRequest{
+		Data: &pb.UploadVideoRequest_Metadata{
+			Metadata: &pb.UploadVideoMetadata{
+				Video:       video,
+				FileName:    part.FileName(),
+				ContentType: part.Header.Get("Content-Type"),
+			},
+		},
+	})
+	if err != nil {
+		_, err = stream.CloseAndRecv()
+		response.Error(c, err)
+		return
+	}
+	buf := make([]byte, uploadChunkSize)
+	for {
+		n, readErr := part.Read(buf)
+		if n > 0 {
+			err = stream.Send(&pb.UploadVideoRequest{
+				Data: &pb.UploadVideoRequest_Chunk{Chunk: buf[:n]},
+			})
+			if err != nil {
+				// the server closed the stream, its status is returned by CloseAndRecv
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			cancel()
+			response.BadRequest(c, readErr.Error())
+			return
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(201, res)
+}
+
+// maxThumbnailSize is the largest thumbnail forwarded to the video service
+const maxThumbnailSize = 2 << 20
+
+// UploadThumbnail uploads the "file" part of a multipart/form-data body as custom thumbnail
+func (r *VideoRoutes) UploadThumbnail(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "file is required")
+		return
+	}
+	if header.Size > maxThumbnailSize {
+		response.BadRequest(c, "image is larger than 2 MiB")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	defer file.Close()
+	image, err := io.ReadAll(io.LimitReader(file, maxThumbnailSize))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	res, err := r.videoClient.UploadThumbnail(c, &pb.UploadThumbnailRequest{
+		Slug:        c.Param("slug"),
+		ContentType: header.Header.Get("Content-Type"),
+		Image:       image,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(200, res)
+}
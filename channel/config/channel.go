@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+)
+
+type server struct {
+	GrpcHost string
+	GrcpPort string
+	HTTPHost string
+	HTTPPort string
+}
+
+type database struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SslMode  string
+}
+
+type logger struct {
+	Level string
+}
+
+// Settings struct
+type Settings struct {
+	Server   *server
+	Database *database
+	Logger   *logger
+}
+
+// GetSettings returns the settings
+func GetSettings() *Settings {
+
+	Settings := &Settings{
+		Server: &server{
+			GrpcHost: os.Getenv("CHANNEL_SVC_GRPC_HOST"),
+			GrcpPort: os.Getenv("CHANNEL_SVC_GRPC_PORT"),
+			HTTPHost: os.Getenv("CHANNEL_SVC_HTTP_HOST"),
+			HTTPPort: os.Getenv("CHANNEL_SVC_HTTP_PORT"),
+		},
+
+		Database: &database{
+			Host:     os.Getenv("CHANNEL_SVC_DB_HOST"),
+			Port:     os.Getenv("CHANNEL_SVC_DB_PORT"),
+			User:     os.Getenv("CHANNEL_SVC_DB_USER"),
+			Password: os.Getenv("CHANNEL_SVC_DB_PASSWORD"),
+			Name:     os.Getenv("CHANNEL_SVC_DB_NAME"),
+			SslMode:  os.Getenv("CHANNEL_SVC_DB_SSLMODE"),
+		},
+
+		Logger: &logger{
+			Level: os.Getenv("CHANNEL_SVC_LOG_LEVEL"),
+		},
+	}
+	return Settings
+}
@@ -0,0 +1,29 @@
+module github.com/iamvasanth07/showcase/channel
+
+go 1.19
+
+require (
+	github.com/iamvasanth07/showcase/common v0.0.0-20230129195247-d85dd6e2b44b
+	github.com/jackc/pgx/v5 v5.3.0
+	github.com/satori/go.uuid v1.2.0
+	google.golang.org/genproto v0.0.0-20230125152338-dcaf20b6aeaa
+	google.golang.org/grpc v1.53.0
+	gorm.io/gorm v1.24.5
+)
+
+require (
+	github.com/golang-jwt/jwt v3.2.2+incompatible // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/crypto v0.6.0 // indirect
+	golang.org/x/net v0.6.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.7.0 // indirect
+	google.golang.org/protobuf v1.28.1 // indirect
+	gorm.io/driver/postgres v1.4.8 // indirect
+)
+
+replace github.com/iamvasanth07/showcase/common => ../common
@@ -0,0 +1,9 @@
+// main package of channel grpc service
+
+package main
+
+import "github.com/iamvasanth07/showcase/channel/service"
+
+func main() {
+	service.RunServer()
+}
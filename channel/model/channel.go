@@ -0,0 +1,43 @@
+// gorm model for the channel table in the database
+
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// HandleRegex matches the normalized handles, without the leading @
+	HandleRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,29}$`)
+)
+
+type Channel struct {
+	gorm.Model
+	Uuid        string `gorm:"primaryKey"`
+	Handle      string `gorm:"uniqueIndex;not null"`
+	Name        string `gorm:"not null"`
+	Description string
+	AvatarUrl   string
+	BannerUrl   string
+	OwnerID     string `gorm:"index;not null"`
+	// SubscriberCount is kept in sync with the subscriptions, see repo.Subscribe
+	SubscriberCount int64 `gorm:"not null;default:0"`
+}
+
+// Hook before create to generate uuid
+func (c *Channel) BeforeCreate(tx *gorm.DB) error {
+	if c.Uuid == "" {
+		c.Uuid = uuid.NewV4().String()
+	}
+	return nil
+}
+
+// NormalizeHandle strips the leading @ and lowercases a handle, handles are
+// unique regardless of the case
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
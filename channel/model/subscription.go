@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// Subscription is a user following a channel
+type Subscription struct {
+	ChannelID    string `gorm:"primaryKey"`
+	SubscriberID string `gorm:"primaryKey;index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,79 @@
+// package repo for the channel service
+
+package repo
+
+import (
+	"github.com/iamvasanth07/showcase/channel/model"
+	"gorm.io/gorm"
+)
+
+type ChannelRepo struct {
+	db *gorm.DB
+}
+
+func NewChannelRepo(db *gorm.DB) *ChannelRepo {
+	return &ChannelRepo{db}
+}
+
+func (r *ChannelRepo) CreateChannel(channel *model.Channel) error {
+	return translate(r.db.Create(channel).Error)
+}
+
+func (r *ChannelRepo) GetChannel(channelId string) (*model.Channel, error) {
+	var channel model.Channel
+	err := r.db.First(&channel, "uuid = ?", channelId).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &channel, nil
+}
+
+func (r *ChannelRepo) GetChannelByHandle(handle string) (*model.Channel, error) {
+	var channel model.Channel
+	err := r.db.First(&channel, "handle = ?", handle).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &channel, nil
+}
+
+// UpdateChannel saves the editable fields of a channel, the subscriber count
+// is only changed by the subscriptions
+func (r *ChannelRepo) UpdateChannel(channel *model.Channel) error {
+	err := r.db.Model(channel).
+		Select("Handle", "Name", "Description", "AvatarUrl", "BannerUrl").
+		Updates(channel).Error
+	return translate(err)
+}
+
+// DeleteChannel deletes a channel and its subscriptions
+func (r *ChannelRepo) DeleteChannel(channelId string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// deleted for good to release the handle
+		res := tx.Unscoped().Where("uuid = ?", channelId).Delete(&model.Channel{})
+		if res.Error != nil {
+			return translate(res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Where("channel_id = ?", channelId).Delete(&model.Subscription{}).Error
+	})
+}
+
+// ListChannelsByOwner returns a page of the channels of a user, oldest first,
+// and their total count
+func (r *ChannelRepo) ListChannelsByOwner(ownerId string, limit int, offset int) ([]model.Channel, int64, error) {
+	var total int64
+	query := r.db.Model(&model.Channel{}).Where("owner_id = ?", ownerId)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translate(err)
+	}
+	var channels []model.Channel
+	err := r.db.Where("owner_id = ?", ownerId).Order("created_at, uuid").
+		Limit(limit).Offset(offset).Find(&channels).Error
+	if err != nil {
+		return nil, 0, translate(err)
+	}
+	return channels, total, nil
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+	"github.com/iamvasanth07/showcase/common"
+	"gorm.io/gorm"
+)
+
+// testDB connects to the postgres database of CHANNEL_SVC_TEST_DSN and
+// empties its tables, the test is skipped without it
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("CHANNEL_SVC_TEST_DSN")
+	if dsn == "" {
+		t.Skip("CHANNEL_SVC_TEST_DSN is not set")
+	}
+	db, err := common.GetDBConnection(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.Channel{}, &model.Subscription{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("TRUNCATE channels, subscriptions").Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestChannelRepo(t *testing.T) {
+	r := NewChannelRepo(testDB(t))
+
+	channel := &model.Channel{Handle: "cooking", Name: "Cooking", OwnerID: "owner-1"}
+	if err := r.CreateChannel(channel); err != nil {
+		t.Fatalf("CreateChannel() = %v", err)
+	}
+	if channel.Uuid == "" {
+		t.Fatal("CreateChannel() did not generate the uuid")
+	}
+	var dup *DuplicateError
+	err := r.CreateChannel(&model.Channel{Handle: "cooking", Name: "Other", OwnerID: "owner-2"})
+	if !errors.As(err, &dup) || dup.Field != "handle" {
+		t.Fatalf("CreateChannel() with a taken handle = %v, want a handle DuplicateError", err)
+	}
+
+	got, err := r.GetChannel(channel.Uuid)
+	if err != nil || got.Handle != "cooking" || got.OwnerID != "owner-1" {
+		t.Fatalf("GetChannel() = %+v, %v", got, err)
+	}
+	if _, err := r.GetChannelByHandle("baking"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetChannelByHandle() of an unknown handle = %v, want ErrNotFound", err)
+	}
+
+	if err := r.Subscribe(channel.Uuid, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	// the subscriber count held by the caller is stale and must not be written back
+	got.Handle = "baking"
+	got.Name = "Baking"
+	got.OwnerID = "owner-2"
+	if err := r.UpdateChannel(got); err != nil {
+		t.Fatalf("UpdateChannel() = %v", err)
+	}
+	updated, err := r.GetChannelByHandle("baking")
+	if err != nil {
+		t.Fatalf("GetChannelByHandle() after the update = %v", err)
+	}
+	if updated.Name != "Baking" || updated.OwnerID != "owner-1" || updated.SubscriberCount != 1 {
+		t.Errorf("updated channel = %+v, want the new name, the same owner and 1 subscriber", updated)
+	}
+	other := &model.Channel{Handle: "gardening", Name: "Gardening", OwnerID: "owner-1"}
+	if err := r.CreateChannel(other); err != nil {
+		t.Fatal(err)
+	}
+	other.Handle = "baking"
+	if err := r.UpdateChannel(other); !errors.As(err, &dup) {
+		t.Errorf("UpdateChannel() to a taken handle = %v, want a DuplicateError", err)
+	}
+
+	channels, total, err := r.ListChannelsByOwner("owner-1", 1, 0)
+	if err != nil || total != 2 || len(channels) != 1 || channels[0].Uuid != channel.Uuid {
+		t.Errorf("ListChannelsByOwner() = %v, %d, %v, want the oldest of 2 channels", channels, total, err)
+	}
+
+	if err := r.DeleteChannel(channel.Uuid); err != nil {
+		t.Fatalf("DeleteChannel() = %v", err)
+	}
+	if _, err := r.GetChannel(channel.Uuid); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetChannel() after the deletion = %v, want ErrNotFound", err)
+	}
+	if err := r.DeleteChannel(channel.Uuid); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteChannel() twice = %v, want ErrNotFound", err)
+	}
+	if _, total, _ := r.ListSubscriptions("user-1", 10, 0); total != 0 {
+		t.Errorf("%d subscriptions left after the deletion, want 0", total)
+	}
+	// the handle of a deleted channel can be taken again
+	if err := r.CreateChannel(&model.Channel{Handle: "baking", Name: "Baking", OwnerID: "owner-3"}); err != nil {
+		t.Errorf("CreateChannel() with the handle of a deleted channel = %v", err)
+	}
+}
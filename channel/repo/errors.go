@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when no channel matches the query
+var ErrNotFound = errors.New("channel not found")
+
+// unique_violation, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const uniqueViolation = "23505"
+
+// DuplicateError is returned when a unique column already holds the value
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s already exists", e.Field)
+}
+
+// translate converts gorm and postgres errors into repo errors
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return &DuplicateError{Field: fieldFromConstraint(pgErr.ConstraintName)}
+	}
+	return err
+}
+
+// fieldFromConstraint extracts the column from gorm's idx_<table>_<column> index names
+func fieldFromConstraint(constraint string) string {
+	for _, field := range []string{"handle"} {
+		if strings.HasSuffix(constraint, "_"+field) {
+			return field
+		}
+	}
+	return constraint
+}
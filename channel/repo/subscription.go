@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"github.com/iamvasanth07/showcase/channel/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Subscribe subscribes a user to a channel. The subscriber count only changes
+// when the subscription is new, in the same transaction, so that concurrent
+// and repeated calls keep it consistent.
+func (r *ChannelRepo) Subscribe(channelId string, subscriberId string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Subscription{
+			ChannelID:    channelId,
+			SubscriberID: subscriberId,
+		})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return translate(res.Error)
+		}
+		return tx.Model(&model.Channel{}).Where("uuid = ?", channelId).
+			UpdateColumn("subscriber_count", gorm.Expr("subscriber_count + 1")).Error
+	})
+}
+
+// Unsubscribe removes the subscription of a user to a channel, if any
+func (r *ChannelRepo) Unsubscribe(channelId string, subscriberId string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("channel_id = ? AND subscriber_id = ?", channelId, subscriberId).
+			Delete(&model.Subscription{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return translate(res.Error)
+		}
+		return tx.Model(&model.Channel{}).Where("uuid = ?", channelId).
+			UpdateColumn("subscriber_count", gorm.Expr("GREATEST(subscriber_count - 1, 0)")).Error
+	})
+}
+
+// ListSubscriptions returns a page of the channels a user follows, most
+// recently followed first, and their total count
+func (r *ChannelRepo) ListSubscriptions(subscriberId string, limit int, offset int) ([]model.Channel, int64, error) {
+	var total int64
+	err := r.db.Model(&model.Subscription{}).Where("subscriber_id = ?", subscriberId).Count(&total).Error
+	if err != nil {
+		return nil, 0, translate(err)
+	}
+	var channels []model.Channel
+	err = r.db.Joins("JOIN subscriptions ON subscriptions.channel_id = channels.uuid").
+		Where("subscriptions.subscriber_id = ?", subscriberId).
+		Order("subscriptions.created_at DESC, channels.uuid").
+		Limit(limit).Offset(offset).Find(&channels).Error
+	if err != nil {
+		return nil, 0, translate(err)
+	}
+	return channels, total, nil
+}
+
+// ListSubscribers returns a page of the subscriptions of a channel, most
+// recent first
+func (r *ChannelRepo) ListSubscribers(channelId string, limit int, offset int) ([]model.Subscription, error) {
+	var subscriptions []model.Subscription
+	err := r.db.Where("channel_id = ?", channelId).
+		Order("created_at DESC, subscriber_id").
+		Limit(limit).Offset(offset).Find(&subscriptions).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+)
+
+func TestSubscriberCountUnderConcurrency(t *testing.T) {
+	db := testDB(t)
+	r := NewChannelRepo(db)
+	channel := &model.Channel{Handle: "cooking", Name: "Cooking", OwnerID: "owner"}
+	if err := r.CreateChannel(channel); err != nil {
+		t.Fatal(err)
+	}
+
+	// every user subscribes twice and the even ones unsubscribe twice, all at once
+	const users = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, users*4)
+	for i := 0; i < users; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		calls := []func(string, string) error{r.Subscribe, r.Subscribe}
+		if i%2 == 0 {
+			calls = append(calls, r.Unsubscribe, r.Unsubscribe)
+		}
+		for _, call := range calls {
+			wg.Add(1)
+			go func(call func(string, string) error) {
+				defer wg.Done()
+				errs <- call(channel.Uuid, user)
+			}(call)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent call = %v", err)
+		}
+	}
+
+	var rows int64
+	if err := db.Model(&model.Subscription{}).Where("channel_id = ?", channel.Uuid).Count(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	stored, err := r.GetChannel(channel.Uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.SubscriberCount != rows {
+		t.Errorf("subscriber count = %d, want the %d subscription rows", stored.SubscriberCount, rows)
+	}
+	// the odd users never unsubscribe
+	if rows < users/2 {
+		t.Errorf("%d subscription rows, want at least %d", rows, users/2)
+	}
+}
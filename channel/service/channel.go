@@ -0,0 +1,220 @@
+// Channel grpc service package
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/iamvasanth07/showcase/channel/config"
+	"github.com/iamvasanth07/showcase/channel/model"
+	"github.com/iamvasanth07/showcase/channel/repo"
+	"github.com/iamvasanth07/showcase/channel/utils"
+	"github.com/iamvasanth07/showcase/common"
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type IChannelService interface {
+	CreateChannel(ctx context.Context, req *pb.CreateChannelRequest) (*pb.CreateChannelResponse, error)
+	GetChannel(ctx context.Context, req *pb.GetChannelRequest) (*pb.GetChannelResponse, error)
+	UpdateChannel(ctx context.Context, req *pb.UpdateChannelRequest) (*pb.UpdateChannelResponse, error)
+	DeleteChannel(ctx context.Context, req *pb.DeleteChannelRequest) (*pb.DeleteChannelResponse, error)
+	ListChannels(ctx context.Context, req *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error)
+	Subscribe(ctx context.Context, req *pb.SubscribeRequest) (*pb.SubscribeResponse, error)
+	Unsubscribe(ctx context.Context, req *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error)
+	ListSubscriptions(ctx context.Context, req *pb.ListSubscriptionsRequest) (*pb.ListSubscriptionsResponse, error)
+	ListSubscribers(ctx context.Context, req *pb.ListSubscribersRequest) (*pb.ListSubscribersResponse, error)
+}
+
+type ChannelServer struct {
+	db       *repo.ChannelRepo
+	authz    *auth.Authorizer
+	log      *log.Logger
+	settings *config.Settings
+	pb.UnimplementedChannelServiceServer
+}
+
+// defaultPageSize is the page size of the listings without a limit
+const defaultPageSize = 20
+
+func NewChannelServer(db *repo.ChannelRepo, logger *log.Logger, settings *config.Settings) *ChannelServer {
+	return &ChannelServer{
+		db:       db,
+		authz:    auth.NewAuthorizer(),
+		log:      logger,
+		settings: settings,
+	}
+}
+
+// CreateChannel creates a channel owned by the caller
+func (s *ChannelServer) CreateChannel(ctx context.Context, req *pb.CreateChannelRequest) (*pb.CreateChannelResponse, error) {
+	s.log.Println("Create channel request received")
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateChannel(req.Channel); err != nil {
+		return nil, err
+	}
+	channel := ProtoToChannel(req.Channel)
+	channel.Uuid = ""
+	channel.OwnerID = identity.UserID
+	if err := s.db.CreateChannel(channel); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.CreateChannelResponse{Channel: ChannelToProto(channel)}, nil
+}
+
+// GetChannel returns a channel by handle or by id
+func (s *ChannelServer) GetChannel(ctx context.Context, req *pb.GetChannelRequest) (*pb.GetChannelResponse, error) {
+	s.log.Println("Get channel request received")
+	var (
+		channel *model.Channel
+		err     error
+	)
+	switch {
+	case req.Handle != "":
+		channel, err = s.db.GetChannelByHandle(model.NormalizeHandle(req.Handle))
+	case req.Id != "":
+		channel, err = s.db.GetChannel(req.Id)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "handle or id is required")
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.GetChannelResponse{Channel: ChannelToProto(channel)}, nil
+}
+
+// UpdateChannel updates a channel of the caller
+func (s *ChannelServer) UpdateChannel(ctx context.Context, req *pb.UpdateChannelRequest) (*pb.UpdateChannelResponse, error) {
+	s.log.Println("Update channel request received")
+	if err := utils.ValidateChannel(req.Channel); err != nil {
+		return nil, err
+	}
+	channel, err := s.ownedChannel(ctx, req.Handle, auth.PermChannelUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	channel.Handle = model.NormalizeHandle(req.Channel.Handle)
+	channel.Name = req.Channel.Name
+	channel.Description = req.Channel.Description
+	channel.AvatarUrl = req.Channel.AvatarUrl
+	channel.BannerUrl = req.Channel.BannerUrl
+	if err := s.db.UpdateChannel(channel); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.UpdateChannelResponse{Channel: ChannelToProto(channel)}, nil
+}
+
+// DeleteChannel deletes a channel of the caller
+func (s *ChannelServer) DeleteChannel(ctx context.Context, req *pb.DeleteChannelRequest) (*pb.DeleteChannelResponse, error) {
+	s.log.Println("Delete channel request received")
+	channel, err := s.ownedChannel(ctx, req.Handle, auth.PermChannelDeleteAny)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.DeleteChannel(channel.Uuid); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.DeleteChannelResponse{Channel: ChannelToProto(channel)}, nil
+}
+
+// ListChannels returns the channels of a user
+func (s *ChannelServer) ListChannels(ctx context.Context, req *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error) {
+	s.log.Println("List channels request received")
+	if err := utils.ValidateListChannels(req); err != nil {
+		return nil, err
+	}
+	page, limit := pageParams(req.Page, req.Limit)
+	channels, total, err := s.db.ListChannelsByOwner(req.OwnerId, int(limit), int((page-1)*limit))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListChannelsResponse{
+		Metadata: &pb.Metadata{
+			Page:  page,
+			Limit: limit,
+			Total: int32(total),
+		},
+	}
+	for i := range channels {
+		res.Channels = append(res.Channels, ChannelToProto(&channels[i]))
+	}
+	return res, nil
+}
+
+// ownedChannel returns the channel with the handle if the caller owns it or
+// holds the permission
+func (s *ChannelServer) ownedChannel(ctx context.Context, handle string, permission string) (*model.Channel, error) {
+	if _, err := s.authz.Authenticate(ctx); err != nil {
+		return nil, err
+	}
+	channel, err := s.db.GetChannelByHandle(model.NormalizeHandle(handle))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if _, err := s.authz.AuthorizeOwner(ctx, channel.OwnerID, permission); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func RunServer() {
+
+	logger := log.New(os.Stdout, "channel-service: ", log.LstdFlags)
+	settings := config.GetSettings()
+	logger.Println("Initializing channel service with settings...")
+	logger.Printf("%v, %v, %v", settings.Database, settings.Server, settings.Logger)
+	conn, err := initDB(settings)
+	if err != nil {
+		log.Fatalf("failed to connect to db: %v", err)
+	}
+	logger.Println("Migration database...")
+	err = migrateDB(conn)
+	if err != nil {
+		log.Fatalf("failed to migrate db: %v", err)
+	}
+
+	db := repo.NewChannelRepo(conn)
+
+	// Starting gRPC server
+	runGRPCServer(settings, db, logger)
+
+}
+
+func initDB(settings *config.Settings) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", settings.Database.Host, settings.Database.Port, settings.Database.User, settings.Database.Password, settings.Database.Name, settings.Database.SslMode)
+	conn, err := common.GetDBConnection(dsn)
+	return conn, err
+}
+
+func migrateDB(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&model.Channel{},
+		&model.Subscription{},
+	)
+}
+
+func runGRPCServer(settings *config.Settings, db *repo.ChannelRepo, logger *log.Logger) {
+	channelServer := NewChannelServer(db, logger, settings)
+	var opts []grpc.ServerOption
+	s := grpc.NewServer(opts...)
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", settings.Server.GrpcHost, settings.Server.GrcpPort))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	pb.RegisterChannelServiceServer(s, channelServer)
+	logger.Println("GRPC Server started on port: " + settings.Server.GrcpPort)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("failed to serve grpc server: %v", err)
+	}
+}
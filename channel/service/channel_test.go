@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+	"github.com/iamvasanth07/showcase/channel/repo"
+	"github.com/iamvasanth07/showcase/common"
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer serves the postgres database of CHANNEL_SVC_TEST_DSN with
+// emptied tables, the test is skipped without it
+func newTestServer(t *testing.T) *ChannelServer {
+	t.Helper()
+	dsn := os.Getenv("CHANNEL_SVC_TEST_DSN")
+	if dsn == "" {
+		t.Skip("CHANNEL_SVC_TEST_DSN is not set")
+	}
+	db, err := common.GetDBConnection(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("TRUNCATE channels, subscriptions").Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewChannelServer(repo.NewChannelRepo(db), log.New(io.Discard, "", 0), nil)
+}
+
+// as returns the context of a request forwarded by the api-gateway for the user
+func as(userID string, permissions ...string) context.Context {
+	kv := []string{auth.UserIDKey, userID}
+	for _, permission := range permissions {
+		kv = append(kv, auth.PermissionsKey, permission)
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+func TestChannelOwnership(t *testing.T) {
+	s := newTestServer(t)
+	anonymous := context.Background()
+
+	_, err := s.CreateChannel(anonymous, &pb.CreateChannelRequest{Channel: &pb.Channel{Handle: "cooking", Name: "Cooking"}})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous CreateChannel() = %v, want Unauthenticated", err)
+	}
+	created, err := s.CreateChannel(as("owner"), &pb.CreateChannelRequest{
+		Channel: &pb.Channel{Id: "chosen", Handle: "@Cooking", Name: "Cooking", OwnerId: "someone-else"},
+	})
+	if err != nil {
+		t.Fatalf("CreateChannel() = %v", err)
+	}
+	if c := created.Channel; c.OwnerId != "owner" || c.Handle != "cooking" || c.Id == "chosen" {
+		t.Fatalf("created channel = %v, want owned by the caller with a normalized handle and a generated id", c)
+	}
+	_, err = s.CreateChannel(as("other"), &pb.CreateChannelRequest{Channel: &pb.Channel{Handle: "COOKING", Name: "Cooking"}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("CreateChannel() with a taken handle = %v, want AlreadyExists", err)
+	}
+
+	for _, req := range []*pb.GetChannelRequest{{Handle: "@cooking"}, {Id: created.Channel.Id}} {
+		res, err := s.GetChannel(anonymous, req)
+		if err != nil || res.Channel.Id != created.Channel.Id {
+			t.Errorf("GetChannel(%v) = %v, %v", req, res, err)
+		}
+	}
+	if _, err := s.GetChannel(anonymous, &pb.GetChannelRequest{Handle: "baking"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetChannel() of an unknown handle = %v, want NotFound", err)
+	}
+	if _, err := s.GetChannel(anonymous, &pb.GetChannelRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetChannel() without handle and id = %v, want InvalidArgument", err)
+	}
+
+	update := func(ctx context.Context, handle, name string) (*pb.UpdateChannelResponse, error) {
+		return s.UpdateChannel(ctx, &pb.UpdateChannelRequest{
+			Handle:  handle,
+			Channel: &pb.Channel{Handle: handle, Name: name, OwnerId: "other"},
+		})
+	}
+	updates := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"anonymous", anonymous, codes.Unauthenticated},
+		{"other user", as("other"), codes.PermissionDenied},
+		{"other permission", as("other", auth.PermChannelDeleteAny), codes.PermissionDenied},
+		{"owner", as("owner"), codes.OK},
+		{"moderator", as("moderator", auth.PermChannelUpdateAny), codes.OK},
+	}
+	for _, tt := range updates {
+		res, err := update(tt.ctx, "cooking", "Cooking by "+tt.name)
+		if status.Code(err) != tt.want {
+			t.Errorf("UpdateChannel() as %s = %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err == nil && (res.Channel.Name != "Cooking by "+tt.name || res.Channel.OwnerId != "owner") {
+			t.Errorf("UpdateChannel() as %s = %v, want the new name and the same owner", tt.name, res.Channel)
+		}
+	}
+	if _, err := update(as("owner"), "baking", "Baking"); status.Code(err) != codes.NotFound {
+		t.Errorf("UpdateChannel() of an unknown handle = %v, want NotFound", err)
+	}
+
+	deletes := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"anonymous", anonymous, codes.Unauthenticated},
+		{"other user", as("other"), codes.PermissionDenied},
+		{"other permission", as("other", auth.PermChannelUpdateAny), codes.PermissionDenied},
+		{"owner", as("owner"), codes.OK},
+		{"owner again", as("owner"), codes.NotFound},
+	}
+	for _, tt := range deletes {
+		_, err := s.DeleteChannel(tt.ctx, &pb.DeleteChannelRequest{Handle: "cooking"})
+		if status.Code(err) != tt.want {
+			t.Errorf("DeleteChannel() as %s = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	moderated, err := s.CreateChannel(as("owner"), &pb.CreateChannelRequest{Channel: &pb.Channel{Handle: "spam", Name: "Spam"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DeleteChannel(as("moderator", auth.PermChannelDeleteAny), &pb.DeleteChannelRequest{Handle: "spam"}); err != nil {
+		t.Errorf("DeleteChannel() as moderator = %v", err)
+	}
+	if _, err := s.db.GetChannel(moderated.Channel.Id); !errors.Is(err, repo.ErrNotFound) {
+		t.Errorf("channel left after the deletion: %v", err)
+	}
+}
+
+func TestChannelConversions(t *testing.T) {
+	channel := ProtoToChannel(&pb.Channel{Id: "id", Handle: " @Cooking ", Name: "Cooking", OwnerId: "owner"})
+	want := model.Channel{Uuid: "id", Handle: "cooking", Name: "Cooking", OwnerID: "owner"}
+	if *channel != want {
+		t.Errorf("ProtoToChannel() = %+v, want %+v", channel, want)
+	}
+	if p := ChannelToProto(nil); p == nil || p.Id != "" {
+		t.Errorf("ChannelToProto(nil) = %v, want an empty channel", p)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/iamvasanth07/showcase/channel/repo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus converts repo errors into grpc status errors, hiding anything unexpected
+func (s *ChannelServer) toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "channel not found")
+	}
+	var dup *repo.DuplicateError
+	if errors.As(err, &dup) {
+		st := status.New(codes.AlreadyExists, dup.Error())
+		detailed, detailErr := st.WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "channel." + dup.Field, Description: dup.Error()},
+			},
+		})
+		if detailErr != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+	s.log.Printf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
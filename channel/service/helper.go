@@ -0,0 +1,48 @@
+package service
+
+import (
+	"time"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+)
+
+// channel model to channel proto
+func ChannelToProto(channel *model.Channel) *pb.Channel {
+
+	channelProto := &pb.Channel{}
+
+	if channel == nil {
+		return channelProto
+	}
+	channelProto.Id = channel.Uuid
+	channelProto.Handle = channel.Handle
+	channelProto.Name = channel.Name
+	channelProto.Description = channel.Description
+	channelProto.AvatarUrl = channel.AvatarUrl
+	channelProto.BannerUrl = channel.BannerUrl
+	channelProto.OwnerId = channel.OwnerID
+	channelProto.SubscriberCount = channel.SubscriberCount
+	if !channel.CreatedAt.IsZero() {
+		channelProto.CreatedAt = channel.CreatedAt.Format(time.RFC3339)
+	}
+	return channelProto
+}
+
+// channel proto to channel model
+func ProtoToChannel(channel *pb.Channel) *model.Channel {
+
+	channelModel := &model.Channel{}
+
+	if channel == nil {
+		return channelModel
+	}
+	channelModel.Uuid = channel.Id
+	channelModel.Handle = model.NormalizeHandle(channel.Handle)
+	channelModel.Name = channel.Name
+	channelModel.Description = channel.Description
+	channelModel.AvatarUrl = channel.AvatarUrl
+	channelModel.BannerUrl = channel.BannerUrl
+	channelModel.OwnerID = channel.OwnerId
+	return channelModel
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+	"github.com/iamvasanth07/showcase/channel/utils"
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Subscribe subscribes the caller to a channel
+func (s *ChannelServer) Subscribe(ctx context.Context, req *pb.SubscribeRequest) (*pb.SubscribeResponse, error) {
+	s.log.Println("Subscribe request received")
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := s.db.GetChannelByHandle(model.NormalizeHandle(req.Handle))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if channel.OwnerID == identity.UserID {
+		return nil, status.Error(codes.FailedPrecondition, "cannot subscribe to your own channel")
+	}
+	if err := s.db.Subscribe(channel.Uuid, identity.UserID); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.SubscribeResponse{Channel: s.reload(channel)}, nil
+}
+
+// Unsubscribe unsubscribes the caller from a channel
+func (s *ChannelServer) Unsubscribe(ctx context.Context, req *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error) {
+	s.log.Println("Unsubscribe request received")
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := s.db.GetChannelByHandle(model.NormalizeHandle(req.Handle))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.Unsubscribe(channel.Uuid, identity.UserID); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.UnsubscribeResponse{Channel: s.reload(channel)}, nil
+}
+
+// ListSubscriptions returns the channels followed by the caller
+func (s *ChannelServer) ListSubscriptions(ctx context.Context, req *pb.ListSubscriptionsRequest) (*pb.ListSubscriptionsResponse, error) {
+	s.log.Println("List subscriptions request received")
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidatePage(req.Page, req.Limit); err != nil {
+		return nil, err
+	}
+	page, limit := pageParams(req.Page, req.Limit)
+	channels, total, err := s.db.ListSubscriptions(identity.UserID, int(limit), int((page-1)*limit))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListSubscriptionsResponse{
+		Metadata: &pb.Metadata{
+			Page:  page,
+			Limit: limit,
+			Total: int32(total),
+		},
+	}
+	for i := range channels {
+		res.Channels = append(res.Channels, ChannelToProto(&channels[i]))
+	}
+	return res, nil
+}
+
+// ListSubscribers returns the subscribers of a channel of the caller
+func (s *ChannelServer) ListSubscribers(ctx context.Context, req *pb.ListSubscribersRequest) (*pb.ListSubscribersResponse, error) {
+	s.log.Println("List subscribers request received")
+	if err := utils.ValidatePage(req.Page, req.Limit); err != nil {
+		return nil, err
+	}
+	channel, err := s.ownedChannel(ctx, req.Handle, auth.PermChannelReadAny)
+	if err != nil {
+		return nil, err
+	}
+	page, limit := pageParams(req.Page, req.Limit)
+	subscriptions, err := s.db.ListSubscribers(channel.Uuid, int(limit), int((page-1)*limit))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListSubscribersResponse{
+		Metadata: &pb.Metadata{
+			Page:  page,
+			Limit: limit,
+			Total: int32(channel.SubscriberCount),
+		},
+	}
+	for _, subscription := range subscriptions {
+		res.Subscribers = append(res.Subscribers, &pb.Subscriber{
+			UserId:       subscription.SubscriberID,
+			SubscribedAt: subscription.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+// reload returns the channel with its current subscriber count, falling back
+// to the given one
+func (s *ChannelServer) reload(channel *model.Channel) *pb.Channel {
+	if current, err := s.db.GetChannel(channel.Uuid); err == nil {
+		channel = current
+	}
+	return ChannelToProto(channel)
+}
+
+// pageParams applies the defaults to the validated page and page size of a listing
+func pageParams(page int32, limit int32) (int32, int32) {
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return page, limit
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/iamvasanth07/showcase/channel/model"
+	pb "github.com/iamvasanth07/showcase/common/protos/channel"
+	"github.com/iamvasanth07/showcase/common/validation"
+)
+
+// MaxPageSize is the largest page of a listing
+const MaxPageSize = 100
+
+// ValidateHandle validates a handle, with or without the leading @
+func ValidateHandle(handle string) error {
+	if handle == "" {
+		return fmt.Errorf("handle is required")
+	}
+	if !model.HandleRegex.MatchString(model.NormalizeHandle(handle)) {
+		return fmt.Errorf("handle must be 3 to 30 letters, digits, dots, dashes or underscores")
+	}
+	return nil
+}
+
+// ValidateChannelName validates the display name of a channel
+func ValidateChannelName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("name is too long")
+	}
+	return nil
+}
+
+// ValidateDescription validates the description of a channel
+func ValidateDescription(description string) error {
+	if len(description) > 5000 {
+		return fmt.Errorf("description is too long")
+	}
+	return nil
+}
+
+// ValidateImageUrl validates an optional avatar or banner url
+func ValidateImageUrl(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http or https url")
+	}
+	if len(value) > 2048 {
+		return fmt.Errorf("url is too long")
+	}
+	return nil
+}
+
+// ValidateChannel validates the fields of a created or updated channel
+func ValidateChannel(channel *pb.Channel) error {
+	v := &validation.Violations{}
+	if channel == nil {
+		v.Add("channel", fmt.Errorf("channel cannot be empty"))
+		return v.Err()
+	}
+	v.Add("channel.handle", ValidateHandle(channel.Handle))
+	v.Add("channel.name", ValidateChannelName(channel.Name))
+	v.Add("channel.description", ValidateDescription(channel.Description))
+	v.Add("channel.avatarUrl", ValidateImageUrl(channel.AvatarUrl))
+	v.Add("channel.bannerUrl", ValidateImageUrl(channel.BannerUrl))
+	return v.Err()
+}
+
+// ValidateListChannels validates the owner and the page of a listing
+func ValidateListChannels(req *pb.ListChannelsRequest) error {
+	v := &validation.Violations{}
+	if req.OwnerId == "" {
+		v.Add("ownerId", fmt.Errorf("ownerId is required"))
+	}
+	addPageViolations(v, req.Page, req.Limit)
+	return v.Err()
+}
+
+// ValidatePage validates the 1-based page and the page size of a listing, 0
+// selects the defaults
+func ValidatePage(page int32, limit int32) error {
+	v := &validation.Violations{}
+	addPageViolations(v, page, limit)
+	return v.Err()
+}
+
+func addPageViolations(v *validation.Violations, page int32, limit int32) {
+	if page < 0 {
+		v.Add("page", fmt.Errorf("page must not be negative"))
+	}
+	if limit < 0 || limit > MaxPageSize {
+		v.Add("limit", fmt.Errorf("limit must be between 1 and %d", MaxPageSize))
+	}
+}
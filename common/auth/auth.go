@@ -0,0 +1,179 @@
+// package auth holds the identity and token types shared between the
+// api-gateway and the grpc services
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadata keys used to forward the authenticated caller to the grpc services
+const (
+	UserIDKey = "x-user-id"
+	EmailKey  = "x-user-email"
+	RolesKey  = "x-user-roles"
+	// PermissionsKey carries the permissions granted by the roles
+	PermissionsKey = "x-user-permissions"
+	// TokenIDKey carries the jti of the access token of the request
+	TokenIDKey = "x-token-id"
+	// EmailVerifiedKey is "true" when the email address of the caller is verified
+	EmailVerifiedKey = "x-user-email-verified"
+	// ScopesKey carries the scopes of the personal access token of the request
+	ScopesKey = "x-token-scopes"
+	// ClientIPKey carries the address of the client of the api-gateway
+	ClientIPKey = "x-client-ip"
+	// UserAgentKey carries the user agent of the client of the api-gateway,
+	// the user-agent key itself names the grpc client
+	UserAgentKey = "x-client-user-agent"
+)
+
+// Claims are the jwt claims minted by the user service
+type Claims struct {
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Roles         []string `json:"roles,omitempty"`
+	Permissions   []string `json:"permissions,omitempty"`
+	jwt.StandardClaims
+}
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	UserID        string
+	Email         string
+	EmailVerified bool
+	Roles         []string
+	Permissions   []string
+	// TokenID is the jti of the access token
+	TokenID string
+	// Scopes limit the requests of the callers authenticated with a personal
+	// access token, empty for the jwts
+	Scopes []string
+}
+
+// HasRole reports whether the identity holds the role
+func (i *Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether one of the roles of the identity grants the permission
+func (i *Identity) HasPermission(permission string) bool {
+	for _, p := range i.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentityFromClaims builds the identity carried by a verified token
+func IdentityFromClaims(claims *Claims) *Identity {
+	return &Identity{
+		UserID:        claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Roles:         claims.Roles,
+		Permissions:   claims.Permissions,
+		TokenID:       claims.Id,
+	}
+}
+
+// AppendToOutgoingContext forwards the identity to the next grpc call
+func AppendToOutgoingContext(ctx context.Context, identity *Identity) context.Context {
+	if identity == nil {
+		return ctx
+	}
+	kv := []string{
+		UserIDKey, identity.UserID,
+		EmailKey, identity.Email,
+	}
+	if identity.TokenID != "" {
+		kv = append(kv, TokenIDKey, identity.TokenID)
+	}
+	if identity.EmailVerified {
+		kv = append(kv, EmailVerifiedKey, "true")
+	}
+	for _, role := range identity.Roles {
+		kv = append(kv, RolesKey, role)
+	}
+	for _, permission := range identity.Permissions {
+		kv = append(kv, PermissionsKey, permission)
+	}
+	for _, scope := range identity.Scopes {
+		kv = append(kv, ScopesKey, scope)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// IdentityFromIncomingContext reads the identity forwarded by the api-gateway
+func IdentityFromIncomingContext(ctx context.Context) (*Identity, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	userID := first(md, UserIDKey)
+	if userID == "" {
+		return nil, false
+	}
+	return &Identity{
+		UserID:        userID,
+		Email:         first(md, EmailKey),
+		EmailVerified: first(md, EmailVerifiedKey) == "true",
+		Roles:         md.Get(RolesKey),
+		Permissions:   md.Get(PermissionsKey),
+		TokenID:       first(md, TokenIDKey),
+		Scopes:        md.Get(ScopesKey),
+	}, true
+}
+
+// AppendClientIP forwards the address of the client to the next grpc call
+func AppendClientIP(ctx context.Context, ip string) context.Context {
+	if ip == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ClientIPKey, ip)
+}
+
+// ClientIPFromIncomingContext reads the client address forwarded by the
+// api-gateway, empty for the calls made without it
+func ClientIPFromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return first(md, ClientIPKey)
+}
+
+// AppendUserAgent forwards the user agent of the client to the next grpc call
+func AppendUserAgent(ctx context.Context, userAgent string) context.Context {
+	if userAgent == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, UserAgentKey, userAgent)
+}
+
+// UserAgentFromIncomingContext reads the user agent forwarded by the
+// api-gateway, empty for the calls made without it
+func UserAgentFromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return first(md, UserAgentKey)
+}
+
+func first(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authorizer is the authorization policy shared by the grpc services: a
+// resource can be changed by its owner and by the callers holding one of the
+// override roles
+type Authorizer struct {
+	overrideRoles []string
+}
+
+// NewAuthorizer returns an authorizer letting the given roles override the
+// ownership checks, admins when none are given
+func NewAuthorizer(overrideRoles ...string) *Authorizer {
+	if len(overrideRoles) == 0 {
+		overrideRoles = []string{RoleAdmin}
+	}
+	return &Authorizer{overrideRoles: overrideRoles}
+}
+
+// Authenticate returns the caller of a request, or an Unauthenticated status
+// error for anonymous requests
+func (a *Authorizer) Authenticate(ctx context.Context) (*Identity, error) {
+	identity, ok := IdentityFromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return identity, nil
+}
+
+// AuthenticateVerified returns the caller of a request if their email
+// address is verified, a PermissionDenied status error otherwise
+func (a *Authorizer) AuthenticateVerified(ctx context.Context) (*Identity, error) {
+	identity, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !identity.EmailVerified {
+		return nil, status.Error(codes.PermissionDenied, "email address is not verified")
+	}
+	return identity, nil
+}
+
+// Require returns the caller if they hold the permission or an override
+// role, a PermissionDenied status error otherwise
+func (a *Authorizer) Require(ctx context.Context, permission string) (*Identity, error) {
+	identity, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !a.allowed(identity, permission) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+	return identity, nil
+}
+
+// AuthorizeOwner returns the caller if they own the resource, hold the
+// permission acting on the resources of any user or an override role, a
+// PermissionDenied status error otherwise. Resources without an owner can
+// only be changed through the permission.
+func (a *Authorizer) AuthorizeOwner(ctx context.Context, ownerID string, permission string) (*Identity, error) {
+	identity, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != "" && identity.UserID == ownerID {
+		return identity, nil
+	}
+	if !a.allowed(identity, permission) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+	return identity, nil
+}
+
+func (a *Authorizer) allowed(identity *Identity, permission string) bool {
+	if permission != "" && identity.HasPermission(permission) {
+		return true
+	}
+	for _, role := range a.overrideRoles {
+		if identity.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
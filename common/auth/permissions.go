@@ -0,0 +1,37 @@
+package auth
+
+// Roles created by the user service
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// Permissions granted through the roles. The ":any" permissions act on the
+// resources of any user, owners need none to change their own.
+const (
+	PermVideoUpdateAny   = "video:update:any"
+	PermVideoDeleteAny   = "video:delete:any"
+	PermVideoReadAny     = "video:read:any"
+	PermChannelUpdateAny = "channel:update:any"
+	PermChannelDeleteAny = "channel:delete:any"
+	PermChannelReadAny   = "channel:read:any"
+	PermRoleManage       = "role:manage"
+	PermUserUpdateAny    = "user:update:any"
+	PermUserDeleteAny    = "user:delete:any"
+	// PermUserUnlock lifts the lockout of the accounts after failed logins
+	PermUserUnlock = "user:unlock"
+)
+
+// Permissions lists every permission known to the services
+var Permissions = []string{
+	PermVideoUpdateAny,
+	PermVideoDeleteAny,
+	PermVideoReadAny,
+	PermChannelUpdateAny,
+	PermChannelDeleteAny,
+	PermChannelReadAny,
+	PermRoleManage,
+	PermUserUpdateAny,
+	PermUserDeleteAny,
+	PermUserUnlock,
+}
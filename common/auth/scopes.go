@@ -0,0 +1,47 @@
+package auth
+
+import "strings"
+
+// PersonalAccessTokenPrefix starts every personal access token so that the
+// secret scanners can recognize them
+const PersonalAccessTokenPrefix = "scpat_"
+
+// Scopes of the personal access tokens, a token only reaches the routes of
+// its scopes
+const (
+	ScopeVideosRead    = "videos:read"
+	ScopeVideosWrite   = "videos:write"
+	ScopeChannelsRead  = "channels:read"
+	ScopeChannelsWrite = "channels:write"
+)
+
+// Scopes lists every scope of the personal access tokens
+var Scopes = []string{
+	ScopeVideosRead,
+	ScopeVideosWrite,
+	ScopeChannelsRead,
+	ScopeChannelsWrite,
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access
+// token rather than a jwt
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// HasScope reports whether the personal access token of the identity grants
+// the scope
+func (i *Identity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken reports whether the identity was authenticated with a
+// personal access token, such tokens always carry scopes
+func (i *Identity) PersonalAccessToken() bool {
+	return len(i.Scopes) > 0
+}
@@ -3,6 +3,8 @@ module github.com/iamvasanth07/showcase/common
 go 1.19
 
 require (
+	github.com/golang-jwt/jwt v3.2.2+incompatible
+	google.golang.org/genproto v0.0.0-20230110181048-76db0878b65f
 	google.golang.org/grpc v1.53.0
 	google.golang.org/protobuf v1.28.1
 	gorm.io/driver/postgres v1.4.8
@@ -20,5 +22,4 @@ require (
 	golang.org/x/net v0.6.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
-	google.golang.org/genproto v0.0.0-20230110181048-76db0878b65f // indirect
 )
@@ -0,0 +1,138 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval throttles the refreshes triggered by unknown key ids and
+// the retries of failed fetches
+const minRefreshInterval = 10 * time.Second
+
+// fetchTimeout bounds a fetch of the key set, whatever the deadline of the
+// callers waiting for it
+const fetchTimeout = 10 * time.Second
+
+// ErrUnavailable is returned while no key set could be fetched
+var ErrUnavailable = errors.New("key set unavailable")
+
+// Fetcher returns the current key set
+type Fetcher func(ctx context.Context) (*Set, error)
+
+// Cache keeps the key set of the issuer, refreshing it when it is older than
+// the ttl or when a token names a key it does not hold yet
+type Cache struct {
+	fetch Fetcher
+	ttl   time.Duration
+
+	mu          sync.Mutex
+	set         *Set
+	keys        map[string]cachedKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// refreshing is closed once the running fetch completes, nil when no
+	// fetch runs
+	refreshing chan struct{}
+}
+
+type cachedKey struct {
+	alg    string
+	public crypto.PublicKey
+}
+
+// NewCache returns an empty cache, filled on first use
+func NewCache(fetch Fetcher, ttl time.Duration) *Cache {
+	return &Cache{fetch: fetch, ttl: ttl}
+}
+
+// Set returns the cached key set
+func (c *Cache) Set(ctx context.Context) (*Set, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale() {
+		c.wait(ctx)
+	}
+	if c.set == nil {
+		return nil, ErrUnavailable
+	}
+	return c.set, nil
+}
+
+// Key returns the public key with the id and its algorithm
+func (c *Cache) Key(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok := c.keys[kid]
+	if c.stale() || (!ok && c.retryable()) {
+		c.wait(ctx)
+		key, ok = c.keys[kid]
+	}
+	if c.set == nil {
+		return nil, "", ErrUnavailable
+	}
+	if !ok {
+		return nil, "", ErrKeyNotFound
+	}
+	return key.public, key.alg, nil
+}
+
+// stale reports whether the set expired and may be fetched again
+func (c *Cache) stale() bool {
+	return (c.set == nil || time.Since(c.fetchedAt) > c.ttl) && c.retryable()
+}
+
+func (c *Cache) retryable() bool {
+	return c.refreshing != nil || time.Since(c.attemptedAt) > minRefreshInterval
+}
+
+// wait starts a refresh unless one runs already and waits for it or for the
+// context. It is called with c.mu held and returns with it held, the lock is
+// released meanwhile.
+func (c *Cache) wait(ctx context.Context) {
+	if c.refreshing == nil {
+		c.attemptedAt = time.Now()
+		c.refreshing = make(chan struct{})
+		go c.refresh()
+	}
+	done := c.refreshing
+	c.mu.Unlock()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	c.mu.Lock()
+}
+
+// refresh fetches the key set, a failed fetch keeps serving the previous keys
+func (c *Cache) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	set, err := c.fetch(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer func() {
+		close(c.refreshing)
+		c.refreshing = nil
+	}()
+	if err != nil {
+		return
+	}
+	keys := make(map[string]cachedKey, len(set.Keys))
+	for _, k := range set.Keys {
+		// the encryption keys never sign tokens
+		if k.Use == "enc" {
+			continue
+		}
+		public, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = cachedKey{alg: k.Algorithm(), public: public}
+	}
+	c.set = set
+	c.keys = keys
+	c.fetchedAt = time.Now()
+}
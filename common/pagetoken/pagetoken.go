@@ -0,0 +1,80 @@
+// package pagetoken encodes the opaque page tokens of the keyset paginated
+// listings. A token holds the sort key and the id of the last item of a
+// page, it is signed so that clients cannot forge positions.
+
+package pagetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for tokens that are malformed, tampered with or
+// used with other listing parameters
+var ErrInvalid = errors.New("invalid page token")
+
+// Token is the position after which the next page starts
+type Token struct {
+	// Key is the sort key of the last item
+	Key string `json:"k"`
+	// ID is the id of the last item, breaking the ties of the sort key
+	ID string `json:"i"`
+	// Params is the digest of the listing parameters the token is valid for
+	Params string `json:"p"`
+}
+
+// Codec signs and verifies the page tokens
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec returns a new codec signing with the secret
+func NewCodec(secret string) *Codec {
+	return &Codec{secret: []byte(secret)}
+}
+
+// Encode returns the opaque form of a token
+func (c *Codec) Encode(token *Token) string {
+	payload, _ := json.Marshal(token)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.sign(encoded)
+}
+
+// Decode verifies a token and checks that it was issued for the given
+// listing parameters
+func (c *Codec) Decode(value string, params string) (*Token, error) {
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return nil, ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	token := &Token{}
+	if err := json.Unmarshal(payload, token); err != nil || token.Params != params {
+		return nil, ErrInvalid
+	}
+	return token, nil
+}
+
+func (c *Codec) sign(encoded string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Params returns the digest of the parameters of a listing, a token is only
+// valid with the parameters of the request it was issued for
+func Params(values ...string) string {
+	hash := sha256.New()
+	for _, value := range values {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
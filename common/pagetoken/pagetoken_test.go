@@ -0,0 +1,56 @@
+package pagetoken
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	codec := NewCodec("secret")
+	params := Params("category", "music")
+	value := codec.Encode(&Token{Key: "2023-01-29T19:52:47Z", ID: "video-1", Params: params})
+	encoded, signature, _ := strings.Cut(value, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"k":"9999","i":"video-9","p":"` + params + `"}`))
+
+	tests := []struct {
+		name   string
+		value  string
+		params string
+	}{
+		{"tampered payload", forged + "." + signature, params},
+		{"tampered signature", encoded + "." + strings.Repeat("A", len(signature)), params},
+		{"missing signature", encoded, params},
+		{"empty token", "", params},
+		{"foreign secret", NewCodec("other").Encode(&Token{Key: "k", ID: "i", Params: params}), params},
+		{"other query", value, Params("category", "sports")},
+		{"query without the filters", value, Params()},
+		{"signed garbage", "bm90IGpzb24." + codec.sign("bm90IGpzb24"), params},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.value, tt.params); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode() = %v, want ErrInvalid", err)
+			}
+		})
+	}
+
+	token, err := codec.Decode(value, params)
+	if err != nil {
+		t.Fatalf("Decode() of a valid token = %v", err)
+	}
+	if token.Key != "2023-01-29T19:52:47Z" || token.ID != "video-1" {
+		t.Errorf("Decode() = %+v, want the position of the token", token)
+	}
+}
+
+func TestParams(t *testing.T) {
+	// the separators keep the values from running into each other
+	if Params("ab", "c") == Params("a", "bc") {
+		t.Error("Params() is ambiguous across the values")
+	}
+	if Params("a", "b") != Params("a", "b") {
+		t.Error("Params() is not deterministic")
+	}
+}
@@ -21,8 +21,18 @@ type VideoServiceClient interface {
 	CreateVideo(ctx context.Context, in *CreateVideoRequest, opts ...grpc.CallOption) (*CreateVideoResponse, error)
 	GetVideo(ctx context.Context, in *GetVideoRequest, opts ...grpc.CallOption) (*GetVideoResponse, error)
 	ListVideos(ctx context.Context, in *ListVideosRequest, opts ...grpc.CallOption) (*ListVideosResponse, error)
+	SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (*SearchVideosResponse, error)
+	GetVideoByChannelId(ctx context.Context, in *GetVideoByChannelIdRequest, opts ...grpc.CallOption) (*GetVideoByChannelIdResponse, error)
 	DeleteVideo(ctx context.Context, in *DeleteVideoRequest, opts ...grpc.CallOption) (*DeleteVideoResponse, error)
 	UpdateVideo(ctx context.Context, in *UpdateVideoRequest, opts ...grpc.CallOption) (*UpdateVideoResponse, error)
+	UploadVideo(ctx context.Context, opts ...grpc.CallOption) (VideoService_UploadVideoClient, error)
+	CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*CreateUploadResponse, error)
+	GetUpload(ctx context.Context, in *GetUploadRequest, opts ...grpc.CallOption) (*GetUploadResponse, error)
+	AppendUpload(ctx context.Context, opts ...grpc.CallOption) (VideoService_AppendUploadClient, error)
+	DeleteUpload(ctx context.Context, in *DeleteUploadRequest, opts ...grpc.CallOption) (*DeleteUploadResponse, error)
+	GetProcessingStatus(ctx context.Context, in *GetProcessingStatusRequest, opts ...grpc.CallOption) (*GetProcessingStatusResponse, error)
+	GetPlaybackUrl(ctx context.Context, in *GetPlaybackUrlRequest, opts ...grpc.CallOption) (*GetPlaybackUrlResponse, error)
+	UploadThumbnail(ctx context.Context, in *UploadThumbnailRequest, opts ...grpc.CallOption) (*UploadThumbnailResponse, error)
 }
 
 type videoServiceClient struct {
@@ -60,6 +70,24 @@ func (c *videoServiceClient) ListVideos(ctx context.Context, in *ListVideosReque
 	return out, nil
 }
 
+func (c *videoServiceClient) SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (*SearchVideosResponse, error) {
+	out := new(SearchVideosResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/SearchVideos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) GetVideoByChannelId(ctx context.Context, in *GetVideoByChannelIdRequest, opts ...grpc.CallOption) (*GetVideoByChannelIdResponse, error) {
+	out := new(GetVideoByChannelIdResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/GetVideoByChannelId", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *videoServiceClient) DeleteVideo(ctx context.Context, in *DeleteVideoRequest, opts ...grpc.CallOption) (*DeleteVideoResponse, error) {
 	out := new(DeleteVideoResponse)
 	err := c.cc.Invoke(ctx, "/video.VideoService/DeleteVideo", in, out, opts...)
@@ -78,6 +106,128 @@ func (c *videoServiceClient) UpdateVideo(ctx context.Context, in *UpdateVideoReq
 	return out, nil
 }
 
+func (c *videoServiceClient) UploadVideo(ctx context.Context, opts ...grpc.CallOption) (VideoService_UploadVideoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VideoService_ServiceDesc.Streams[0], "/video.VideoService/UploadVideo", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &videoServiceUploadVideoClient{stream}
+	return x, nil
+}
+
+type VideoService_UploadVideoClient interface {
+	Send(*UploadVideoRequest) error
+	CloseAndRecv() (*UploadVideoResponse, error)
+	grpc.ClientStream
+}
+
+type videoServiceUploadVideoClient struct {
+	grpc.ClientStream
+}
+
+func (x *videoServiceUploadVideoClient) Send(m *UploadVideoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *videoServiceUploadVideoClient) CloseAndRecv() (*UploadVideoResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadVideoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *videoServiceClient) CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*CreateUploadResponse, error) {
+	out := new(CreateUploadResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/CreateUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) GetUpload(ctx context.Context, in *GetUploadRequest, opts ...grpc.CallOption) (*GetUploadResponse, error) {
+	out := new(GetUploadResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/GetUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) AppendUpload(ctx context.Context, opts ...grpc.CallOption) (VideoService_AppendUploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VideoService_ServiceDesc.Streams[1], "/video.VideoService/AppendUpload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &videoServiceAppendUploadClient{stream}
+	return x, nil
+}
+
+type VideoService_AppendUploadClient interface {
+	Send(*AppendUploadRequest) error
+	CloseAndRecv() (*AppendUploadResponse, error)
+	grpc.ClientStream
+}
+
+type videoServiceAppendUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *videoServiceAppendUploadClient) Send(m *AppendUploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *videoServiceAppendUploadClient) CloseAndRecv() (*AppendUploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(AppendUploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *videoServiceClient) DeleteUpload(ctx context.Context, in *DeleteUploadRequest, opts ...grpc.CallOption) (*DeleteUploadResponse, error) {
+	out := new(DeleteUploadResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/DeleteUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) GetProcessingStatus(ctx context.Context, in *GetProcessingStatusRequest, opts ...grpc.CallOption) (*GetProcessingStatusResponse, error) {
+	out := new(GetProcessingStatusResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/GetProcessingStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) GetPlaybackUrl(ctx context.Context, in *GetPlaybackUrlRequest, opts ...grpc.CallOption) (*GetPlaybackUrlResponse, error) {
+	out := new(GetPlaybackUrlResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/GetPlaybackUrl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) UploadThumbnail(ctx context.Context, in *UploadThumbnailRequest, opts ...grpc.CallOption) (*UploadThumbnailResponse, error) {
+	out := new(UploadThumbnailResponse)
+	err := c.cc.Invoke(ctx, "/video.VideoService/UploadThumbnail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VideoServiceServer is the server API for VideoService service.
 // All implementations must embed UnimplementedVideoServiceServer
 // for forward compatibility
@@ -85,8 +235,18 @@ type VideoServiceServer interface {
 	CreateVideo(context.Context, *CreateVideoRequest) (*CreateVideoResponse, error)
 	GetVideo(context.Context, *GetVideoRequest) (*GetVideoResponse, error)
 	ListVideos(context.Context, *ListVideosRequest) (*ListVideosResponse, error)
+	SearchVideos(context.Context, *SearchVideosRequest) (*SearchVideosResponse, error)
+	GetVideoByChannelId(context.Context, *GetVideoByChannelIdRequest) (*GetVideoByChannelIdResponse, error)
 	DeleteVideo(context.Context, *DeleteVideoRequest) (*DeleteVideoResponse, error)
 	UpdateVideo(context.Context, *UpdateVideoRequest) (*UpdateVideoResponse, error)
+	UploadVideo(VideoService_UploadVideoServer) error
+	CreateUpload(context.Context, *CreateUploadRequest) (*CreateUploadResponse, error)
+	GetUpload(context.Context, *GetUploadRequest) (*GetUploadResponse, error)
+	AppendUpload(VideoService_AppendUploadServer) error
+	DeleteUpload(context.Context, *DeleteUploadRequest) (*DeleteUploadResponse, error)
+	GetProcessingStatus(context.Context, *GetProcessingStatusRequest) (*GetProcessingStatusResponse, error)
+	GetPlaybackUrl(context.Context, *GetPlaybackUrlRequest) (*GetPlaybackUrlResponse, error)
+	UploadThumbnail(context.Context, *UploadThumbnailRequest) (*UploadThumbnailResponse, error)
 	mustEmbedUnimplementedVideoServiceServer()
 }
 
@@ -103,12 +263,42 @@ func (UnimplementedVideoServiceServer) GetVideo(context.Context, *GetVideoReques
 func (UnimplementedVideoServiceServer) ListVideos(context.Context, *ListVideosRequest) (*ListVideosResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListVideos not implemented")
 }
+func (UnimplementedVideoServiceServer) SearchVideos(context.Context, *SearchVideosRequest) (*SearchVideosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchVideos not implemented")
+}
+func (UnimplementedVideoServiceServer) GetVideoByChannelId(context.Context, *GetVideoByChannelIdRequest) (*GetVideoByChannelIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVideoByChannelId not implemented")
+}
 func (UnimplementedVideoServiceServer) DeleteVideo(context.Context, *DeleteVideoRequest) (*DeleteVideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteVideo not implemented")
 }
 func (UnimplementedVideoServiceServer) UpdateVideo(context.Context, *UpdateVideoRequest) (*UpdateVideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateVideo not implemented")
 }
+func (UnimplementedVideoServiceServer) UploadVideo(VideoService_UploadVideoServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadVideo not implemented")
+}
+func (UnimplementedVideoServiceServer) CreateUpload(context.Context, *CreateUploadRequest) (*CreateUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUpload not implemented")
+}
+func (UnimplementedVideoServiceServer) GetUpload(context.Context, *GetUploadRequest) (*GetUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpload not implemented")
+}
+func (UnimplementedVideoServiceServer) AppendUpload(VideoService_AppendUploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method AppendUpload not implemented")
+}
+func (UnimplementedVideoServiceServer) DeleteUpload(context.Context, *DeleteUploadRequest) (*DeleteUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUpload not implemented")
+}
+func (UnimplementedVideoServiceServer) GetProcessingStatus(context.Context, *GetProcessingStatusRequest) (*GetProcessingStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProcessingStatus not implemented")
+}
+func (UnimplementedVideoServiceServer) GetPlaybackUrl(context.Context, *GetPlaybackUrlRequest) (*GetPlaybackUrlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlaybackUrl not implemented")
+}
+func (UnimplementedVideoServiceServer) UploadThumbnail(context.Context, *UploadThumbnailRequest) (*UploadThumbnailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadThumbnail not implemented")
+}
 func (UnimplementedVideoServiceServer) mustEmbedUnimplementedVideoServiceServer() {}
 
 // UnsafeVideoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -176,6 +366,42 @@ func _VideoService_ListVideos_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VideoService_SearchVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).SearchVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/SearchVideos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).SearchVideos(ctx, req.(*SearchVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_GetVideoByChannelId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVideoByChannelIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).GetVideoByChannelId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/GetVideoByChannelId",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).GetVideoByChannelId(ctx, req.(*GetVideoByChannelIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VideoService_DeleteVideo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteVideoRequest)
 	if err := dec(in); err != nil {
@@ -212,6 +438,166 @@ func _VideoService_UpdateVideo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VideoService_UploadVideo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VideoServiceServer).UploadVideo(&videoServiceUploadVideoServer{stream})
+}
+
+type VideoService_UploadVideoServer interface {
+	SendAndClose(*UploadVideoResponse) error
+	Recv() (*UploadVideoRequest, error)
+	grpc.ServerStream
+}
+
+type videoServiceUploadVideoServer struct {
+	grpc.ServerStream
+}
+
+func (x *videoServiceUploadVideoServer) SendAndClose(m *UploadVideoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *videoServiceUploadVideoServer) Recv() (*UploadVideoRequest, error) {
+	m := new(UploadVideoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _VideoService_CreateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).CreateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/CreateUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).CreateUpload(ctx, req.(*CreateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_GetUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).GetUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/GetUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).GetUpload(ctx, req.(*GetUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_AppendUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VideoServiceServer).AppendUpload(&videoServiceAppendUploadServer{stream})
+}
+
+type VideoService_AppendUploadServer interface {
+	SendAndClose(*AppendUploadResponse) error
+	Recv() (*AppendUploadRequest, error)
+	grpc.ServerStream
+}
+
+type videoServiceAppendUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *videoServiceAppendUploadServer) SendAndClose(m *AppendUploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *videoServiceAppendUploadServer) Recv() (*AppendUploadRequest, error) {
+	m := new(AppendUploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _VideoService_DeleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).DeleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/DeleteUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).DeleteUpload(ctx, req.(*DeleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_GetProcessingStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProcessingStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).GetProcessingStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/GetProcessingStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).GetProcessingStatus(ctx, req.(*GetProcessingStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_GetPlaybackUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlaybackUrlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).GetPlaybackUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/GetPlaybackUrl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).GetPlaybackUrl(ctx, req.(*GetPlaybackUrlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_UploadThumbnail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadThumbnailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).UploadThumbnail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/video.VideoService/UploadThumbnail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).UploadThumbnail(ctx, req.(*UploadThumbnailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VideoService_ServiceDesc is the grpc.ServiceDesc for VideoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -231,6 +617,14 @@ var VideoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListVideos",
 			Handler:    _VideoService_ListVideos_Handler,
 		},
+		{
+			MethodName: "SearchVideos",
+			Handler:    _VideoService_SearchVideos_Handler,
+		},
+		{
+			MethodName: "GetVideoByChannelId",
+			Handler:    _VideoService_GetVideoByChannelId_Handler,
+		},
 		{
 			MethodName: "DeleteVideo",
 			Handler:    _VideoService_DeleteVideo_Handler,
@@ -239,7 +633,42 @@ var VideoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateVideo",
 			Handler:    _VideoService_UpdateVideo_Handler,
 		},
+		{
+			MethodName: "CreateUpload",
+			Handler:    _VideoService_CreateUpload_Handler,
+		},
+		{
+			MethodName: "GetUpload",
+			Handler:    _VideoService_GetUpload_Handler,
+		},
+		{
+			MethodName: "DeleteUpload",
+			Handler:    _VideoService_DeleteUpload_Handler,
+		},
+		{
+			MethodName: "GetProcessingStatus",
+			Handler:    _VideoService_GetProcessingStatus_Handler,
+		},
+		{
+			MethodName: "GetPlaybackUrl",
+			Handler:    _VideoService_GetPlaybackUrl_Handler,
+		},
+		{
+			MethodName: "UploadThumbnail",
+			Handler:    _VideoService_UploadThumbnail_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadVideo",
+			Handler:       _VideoService_UploadVideo_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AppendUpload",
+			Handler:       _VideoService_AppendUpload_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/video/video.proto",
 }
@@ -0,0 +1,40 @@
+// package validation reports the validation failures of the requests of the
+// services as InvalidArgument statuses with field violations, which the
+// api-gateway renders as error details
+
+package validation
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Violations collects the validation failures of a request
+type Violations struct {
+	fields []*errdetails.BadRequest_FieldViolation
+}
+
+// Add records a violation for the field if err is not nil
+func (v *Violations) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+	v.fields = append(v.fields, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: err.Error(),
+	})
+}
+
+// Err returns an InvalidArgument status carrying every violation, or nil
+func (v *Violations) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, "invalid request")
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v.fields})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,36 @@
+// mock-oidc runs the local OpenID Connect provider of the oidctest package,
+// register it with the user service as
+//
+//	USER_SVC_OIDC_PROVIDERS=mock
+//	USER_SVC_OIDC_MOCK_ISSUER=http://localhost:9000
+//	USER_SVC_OIDC_MOCK_CLIENT_ID=showcase
+//	USER_SVC_OIDC_MOCK_CLIENT_SECRET=showcase-secret
+//
+// and open /api/v1/user/oidc/mock/login?login_hint=<email> on the gateway.
+
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/iamvasanth07/showcase/user/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer url, as reached by the browser and the user service")
+	clientID := flag.String("client-id", "showcase", "client id of the user service")
+	clientSecret := flag.String("client-secret", "showcase-secret", "client secret of the user service")
+	unverified := flag.Bool("unverified", false, "issue tokens with email_verified false")
+	flag.Parse()
+
+	provider, err := oidctest.New(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("failed to create the provider: %v", err)
+	}
+	provider.EmailVerified = !*unverified
+	log.Printf("mock oidc provider %s listening on %s", provider.Issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, provider))
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type server struct {
@@ -26,22 +27,116 @@ type logger struct {
 }
 
 type jwt struct {
+	// KeysDir holds the PEM private keys signing the tokens
+	KeysDir string
+	// Algorithm of the generated keys, RS256 or EdDSA
+	Algorithm string
+	// Rotation is the lifetime of a signing key in hours, 0 disables the
+	// generation of new keys
+	Rotation int
+	// Grace is the time in hours a retired key keeps verifying tokens
+	Grace int
+	// Expiry is the lifetime of the access tokens in minutes
+	Expiry int
+	// RefreshExpiry is the lifetime of the refresh tokens in hours
+	RefreshExpiry int
+	Issuer        string
+}
+
+type pageToken struct {
+	Secret string
+}
+
+// SMTP holds the settings of the mail server
+type SMTP struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+}
+
+type mail struct {
+	// Backend is smtp, file or memory
+	Backend string
+	From    string
+	// Dir receives the messages of the file backend
+	Dir  string
+	SMTP *SMTP
+}
+
+type passwordReset struct {
+	// URL of the reset page, the token is appended as the token query parameter
+	URL string
+	// Expiry is the lifetime of the reset tokens in minutes
+	Expiry int
+}
+
+type verification struct {
+	// Secret signs the verification tokens
 	Secret string
+	// URL of the verification page, the token is appended as the token query parameter
+	URL string
+	// Expiry is the lifetime of the verification links in hours
 	Expiry int
 }
 
+type twoFactor struct {
+	// Issuer names the account in the authenticator apps
+	Issuer string
+	// Key seals the authenticator app secrets stored in the database
+	Key string
+}
+
+// OIDCProvider holds the registration of the service at an OpenID Connect provider
+type OIDCProvider struct {
+	// Name identifies the provider in the routes, lower case
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes default to openid, email and profile
+	Scopes []string
+}
+
+type oidc struct {
+	// CallbackURL is the gateway prefix of the provider callbacks, a
+	// provider redirects to <CallbackURL>/<name>/callback
+	CallbackURL string
+	Providers   []*OIDCProvider
+}
+
+// bootstrap grants the admin role to the user with AdminEmail while no user holds it
+type bootstrap struct {
+	AdminEmail string
+}
+
 // Settings struct
 type Settings struct {
-	Server   *server
-	Database *database
-	Logger   *logger
-	JWT      *jwt
+	Server    *server
+	Database  *database
+	Logger    *logger
+	JWT       *jwt
+	PageToken *pageToken
+	Bootstrap *bootstrap
+	Mail      *mail
+	// PasswordReset configures the reset emails
+	PasswordReset *passwordReset
+	// Verification configures the email verification links
+	Verification *verification
+	TwoFactor    *twoFactor
+	// OIDC configures the logins with external identity providers
+	OIDC *oidc
 }
 
 // GetSettings returns the settings
 func GetSettings() *Settings {
 
 	jwt_expiry, _ := strconv.Atoi(os.Getenv("USER_SVC_JWT_EXPIRY"))
+	refresh_expiry, _ := strconv.Atoi(os.Getenv("USER_SVC_REFRESH_TOKEN_EXPIRY"))
+	key_rotation, _ := strconv.Atoi(os.Getenv("USER_SVC_JWT_KEY_ROTATION"))
+	key_grace, _ := strconv.Atoi(os.Getenv("USER_SVC_JWT_KEY_GRACE"))
+	reset_expiry, _ := strconv.Atoi(os.Getenv("USER_SVC_PASSWORD_RESET_EXPIRY"))
+	verification_expiry, _ := strconv.Atoi(os.Getenv("USER_SVC_VERIFICATION_EXPIRY"))
 
 	Settings := &Settings{
 		Server: &server{
@@ -64,9 +159,86 @@ func GetSettings() *Settings {
 			Level: os.Getenv("USER_SVC_LOG_LEVEL"),
 		},
 		JWT: &jwt{
-			Secret: os.Getenv("USER_SVC_JWT_SECRET"),
-			Expiry: jwt_expiry,
+			KeysDir:       os.Getenv("USER_SVC_JWT_KEYS_DIR"),
+			Algorithm:     os.Getenv("USER_SVC_JWT_ALGORITHM"),
+			Rotation:      key_rotation,
+			Grace:         key_grace,
+			Expiry:        jwt_expiry,
+			RefreshExpiry: refresh_expiry,
+			Issuer:        os.Getenv("USER_SVC_JWT_ISSUER"),
+		},
+		PageToken: &pageToken{
+			Secret: os.Getenv("USER_SVC_PAGE_TOKEN_SECRET"),
+		},
+		Bootstrap: &bootstrap{
+			AdminEmail: os.Getenv("USER_SVC_ADMIN_EMAIL"),
+		},
+		Mail: &mail{
+			Backend: os.Getenv("USER_SVC_MAILER"),
+			From:    os.Getenv("USER_SVC_MAIL_FROM"),
+			Dir:     os.Getenv("USER_SVC_MAIL_DIR"),
+			SMTP: &SMTP{
+				Host:     os.Getenv("USER_SVC_SMTP_HOST"),
+				Port:     os.Getenv("USER_SVC_SMTP_PORT"),
+				User:     os.Getenv("USER_SVC_SMTP_USER"),
+				Password: os.Getenv("USER_SVC_SMTP_PASSWORD"),
+			},
+		},
+		PasswordReset: &passwordReset{
+			URL:    os.Getenv("USER_SVC_PASSWORD_RESET_URL"),
+			Expiry: reset_expiry,
+		},
+		Verification: &verification{
+			Secret: os.Getenv("USER_SVC_VERIFICATION_SECRET"),
+			URL:    os.Getenv("USER_SVC_VERIFICATION_URL"),
+			Expiry: verification_expiry,
+		},
+		TwoFactor: &twoFactor{
+			Issuer: os.Getenv("USER_SVC_TOTP_ISSUER"),
+			Key:    os.Getenv("USER_SVC_TOTP_KEY"),
+		},
+		OIDC: &oidc{
+			CallbackURL: os.Getenv("USER_SVC_OIDC_CALLBACK_URL"),
+			Providers:   oidcProviders(os.Getenv("USER_SVC_OIDC_PROVIDERS")),
 		},
 	}
 	return Settings
 }
+
+// MissingSecrets returns the environment variables of the secrets left
+// empty, the service does not start without them
+func (s *Settings) MissingSecrets() []string {
+	var missing []string
+	if s.PageToken.Secret == "" {
+		missing = append(missing, "USER_SVC_PAGE_TOKEN_SECRET")
+	}
+	if s.Verification.Secret == "" {
+		missing = append(missing, "USER_SVC_VERIFICATION_SECRET")
+	}
+	if s.TwoFactor.Key == "" {
+		missing = append(missing, "USER_SVC_TOTP_KEY")
+	}
+	return missing
+}
+
+// oidcProviders reads the providers named in a comma separated list, each
+// configured with USER_SVC_OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and
+// the optional space separated _SCOPES
+func oidcProviders(names string) []*OIDCProvider {
+	var providers []*OIDCProvider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "USER_SVC_OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, &OIDCProvider{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		})
+	}
+	return providers
+}
@@ -0,0 +1,275 @@
+// package keys manages the private keys signing the tokens. The keys are PEM
+// files of a directory named after their key id, the newest one signs and the
+// previous ones stay published for a grace window after their retirement so
+// that the tokens they signed can still be verified.
+
+package keys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/iamvasanth07/showcase/common/jwks"
+)
+
+// rsaKeySize is the size of the generated RSA keys
+const rsaKeySize = 2048
+
+// Key is a signing key of the ring
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	CreatedAt time.Time
+	// RetiredAt is the creation time of the next key, zero for the active key
+	RetiredAt time.Time
+}
+
+// Method returns the jwt signing method of the key
+func (k *Key) Method() jwt.SigningMethod {
+	method, _ := jwks.SigningMethod(k.Algorithm)
+	return method
+}
+
+// SigningKey returns the private key in the form expected by the signing method
+func (k *Key) SigningKey() interface{} {
+	if ed, ok := k.Private.(ed25519.PrivateKey); ok {
+		return ed
+	}
+	return k.Private
+}
+
+// KeyRing holds the signing keys loaded from a directory
+type KeyRing struct {
+	dir       string
+	algorithm string
+	rotation  time.Duration
+	grace     time.Duration
+	log       *log.Logger
+
+	mu   sync.RWMutex
+	keys []*Key // newest first
+}
+
+// NewKeyRing loads the keys of the directory, generating a key of the
+// algorithm when none is usable. A zero rotation never generates new keys
+// once one exists, the keys are then rotated by adding files.
+func NewKeyRing(dir string, algorithm string, rotation time.Duration, grace time.Duration, logger *log.Logger) (*KeyRing, error) {
+	if _, err := jwks.SigningMethod(algorithm); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	r := &KeyRing{
+		dir:       dir,
+		algorithm: algorithm,
+		rotation:  rotation,
+		grace:     grace,
+		log:       logger,
+	}
+	if err := r.Rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Active returns the key signing the new tokens
+func (r *KeyRing) Active() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[0]
+}
+
+// PublicKeys returns the key set verifying the tokens signed by the active
+// key and by the keys retired within the grace window
+func (r *KeyRing) PublicKeys() (*jwks.Set, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	set := &jwks.Set{Keys: make([]jwks.Key, 0, len(r.keys))}
+	for _, key := range r.keys {
+		public, err := jwks.NewKey(key.ID, key.Private.Public())
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, public)
+	}
+	return set, nil
+}
+
+// Rotate reloads the directory, generates a new key when the active one is
+// older than the rotation period and drops the keys past their grace window
+func (r *KeyRing) Rotate(now time.Time) error {
+	keys, err := r.load()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 || (r.rotation > 0 && now.Sub(keys[0].CreatedAt) >= r.rotation) {
+		key, err := r.generate(now)
+		if err != nil {
+			return err
+		}
+		r.log.Printf("generated signing key %s", key.ID)
+		keys = append([]*Key{key}, keys...)
+	}
+	valid := keys[:1]
+	for i := 1; i < len(keys); i++ {
+		keys[i].RetiredAt = keys[i-1].CreatedAt
+		if now.Sub(keys[i].RetiredAt) > r.grace {
+			r.log.Printf("removing signing key %s retired at %s", keys[i].ID, keys[i].RetiredAt.Format(time.RFC3339))
+			if err := os.Remove(r.path(keys[i].ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				r.log.Printf("failed to remove signing key %s: %v", keys[i].ID, err)
+			}
+			continue
+		}
+		valid = append(valid, keys[i])
+	}
+	r.mu.Lock()
+	r.keys = valid
+	r.mu.Unlock()
+	return nil
+}
+
+// Run rotates the keys every interval until ctx is done
+func (r *KeyRing) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := r.Rotate(now); err != nil {
+				r.log.Printf("failed to rotate the signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// load reads the keys of the directory, newest first
+func (r *KeyRing) load() ([]*Key, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*Key
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+		key, err := r.read(entry)
+		if err != nil {
+			r.log.Printf("skipping signing key %s: %v", entry.Name(), err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID > keys[j].ID
+		}
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *KeyRing) read(entry os.DirEntry) (*Key, error) {
+	info, err := entry.Info()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(r.dir, entry.Name()))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("not a PKCS #8 private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{
+		ID:        strings.TrimSuffix(entry.Name(), ".pem"),
+		CreatedAt: info.ModTime(),
+	}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private = jwks.AlgRS256, private
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private = jwks.AlgEdDSA, private
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
+
+// generate creates a key of the algorithm and writes it to the directory
+func (r *KeyRing) generate(now time.Time) (*Key, error) {
+	var private crypto.Signer
+	var err error
+	switch r.algorithm {
+	case jwks.AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	default:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	key := &Key{
+		ID:        now.UTC().Format("20060102") + "-" + hex.EncodeToString(id),
+		Algorithm: r.algorithm,
+		Private:   private,
+		CreatedAt: now,
+	}
+	// written aside and renamed so that a reload never reads a partial key
+	tmp, err := os.CreateTemp(r.dir, ".key-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := pem.Encode(tmp, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chtimes(tmp.Name(), now, now); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), r.path(key.ID)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *KeyRing) path(id string) string {
+	return filepath.Join(r.dir, id+".pem")
+}
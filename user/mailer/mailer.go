@@ -0,0 +1,36 @@
+// package mailer delivers the emails of the user service
+
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iamvasanth07/showcase/user/config"
+)
+
+// Message is a plain text email
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New returns the mailer selected in the settings
+func New(settings *config.Settings) (Mailer, error) {
+	switch settings.Mail.Backend {
+	case "", "memory":
+		return NewMemoryMailer(), nil
+	case "file":
+		return NewFileMailer(settings.Mail.Dir)
+	case "smtp":
+		return NewSMTPMailer(settings.Mail.SMTP)
+	}
+	return nil, fmt.Errorf("unknown mailer %q", settings.Mail.Backend)
+}
@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/smtp"
+
+	"github.com/iamvasanth07/showcase/user/config"
+)
+
+// SMTPMailer delivers the messages to a mail server
+type SMTPMailer struct {
+	host string
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns a new mailer sending through the server of the settings
+func NewSMTPMailer(settings *config.SMTP) (*SMTPMailer, error) {
+	if settings.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	port := settings.Port
+	if port == "" {
+		port = "587"
+	}
+	m := &SMTPMailer{host: settings.Host, addr: net.JoinHostPort(settings.Host, port)}
+	if settings.User != "" {
+		m.auth = smtp.PlainAuth("", settings.User, settings.Password, settings.Host)
+	}
+	return m, nil
+}
+
+// Send delivers the message like smtp.SendMail, the connection is closed
+// once the context is done so that a stalled server does not hold the sender
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := format(msg)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+	if err := m.send(conn, msg, data); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *SMTPMailer) send(conn net.Conn, msg *Message, data []byte) error {
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(msg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
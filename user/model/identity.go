@@ -0,0 +1,33 @@
+// External identity data model
+
+package model
+
+import "time"
+
+// ExternalIdentity links the account of an OpenID Connect provider to a user
+type ExternalIdentity struct {
+	// Provider is the configured name of the provider and Subject the
+	// identifier of the account at the provider, stable across email changes
+	Provider string `gorm:"primaryKey"`
+	Subject  string `gorm:"primaryKey"`
+	UserID   string `gorm:"not null;index"`
+	// Email is the address the provider reported on the last login
+	Email       string
+	LastLoginAt time.Time
+	CreatedAt   time.Time
+}
+
+// OIDCLogin is a login started at a provider, consumed by the callback
+// carrying its state
+type OIDCLogin struct {
+	// StateHash is the sha256 of the state parameter
+	StateHash string `gorm:"primaryKey"`
+	Provider  string `gorm:"not null"`
+	// Nonce binds the id token to the login
+	Nonce string `gorm:"not null"`
+	// CodeVerifier is the PKCE verifier of the code challenge sent to the
+	// provider
+	CodeVerifier string    `gorm:"not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
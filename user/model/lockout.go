@@ -0,0 +1,52 @@
+// Login lockout data model
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// audit events of the lockouts
+const (
+	AuditLocked   = "locked"
+	AuditUnlocked = "unlocked"
+)
+
+// LoginThrottle counts the failed logins of an account or a client address
+type LoginThrottle struct {
+	// Key is the email of the account or the address of the client, prefixed
+	// with their kind
+	Key           string `gorm:"primaryKey"`
+	Failures      int
+	LastFailureAt time.Time `gorm:"index"`
+	// LockedUntil rejects every login of the key until then
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
+
+// AuditEvent records a lock or an unlock of a login throttle
+type AuditEvent struct {
+	ID    string `gorm:"primaryKey"`
+	Event string `gorm:"not null"`
+	// Key is the key of the throttle
+	Key string `gorm:"not null;index"`
+	// UserID is the user of the account, when it exists
+	UserID string `gorm:"index"`
+	// ActorID is the admin who unlocked the throttle
+	ActorID     string
+	IP          string
+	Failures    int
+	LockedUntil *time.Time
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+// Hook before create to generate the id
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.NewV4().String()
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Outbox data model
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// kinds of the outbox emails
+const (
+	EmailPasswordReset = "password_reset"
+	EmailVerification  = "verification"
+	EmailNewDevice     = "new_device"
+)
+
+// OutboxEmail is an email waiting for delivery. It is written in the
+// transaction of the change it announces and delivered in the background.
+type OutboxEmail struct {
+	ID        string `gorm:"primaryKey"`
+	Recipient string `gorm:"not null"`
+	// UserID and Kind tell which user the email was sent to and why, they
+	// rate limit the emails sent on request
+	UserID        string `gorm:"index:idx_outbox_user_kind"`
+	Kind          string `gorm:"index:idx_outbox_user_kind"`
+	Subject       string `gorm:"not null"`
+	Body          string `gorm:"not null"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     string
+	SentAt        *time.Time `gorm:"index"`
+	CreatedAt     time.Time
+}
+
+// Hook before create to generate the id and schedule the first attempt
+func (e *OutboxEmail) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.NewV4().String()
+	}
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = time.Now()
+	}
+	return nil
+}
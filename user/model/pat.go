@@ -0,0 +1,38 @@
+// Personal access token data model
+
+package model
+
+import (
+	"regexp"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+var TokenNameRegex = regexp.MustCompile(`^[a-zA-Z0-9 ._\-]{1,64}$`)
+
+// PersonalAccessToken authenticates the automations of a user within its scopes
+type PersonalAccessToken struct {
+	ID     string `gorm:"primaryKey"`
+	UserID string `gorm:"not null;index"`
+	Name   string `gorm:"not null"`
+	// Prefix is the start of the token, shown in the listings
+	Prefix string `gorm:"not null"`
+	// TokenHash is the sha256 of the token, the token itself is shown once
+	TokenHash  string    `gorm:"not null;uniqueIndex"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	LastUsedIP string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Hook before create to generate the id
+func (t *PersonalAccessToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.NewV4().String()
+	}
+	return nil
+}
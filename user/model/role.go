@@ -0,0 +1,55 @@
+// Role data model
+
+package model
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+)
+
+var RoleNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// Role is a named set of permissions granted to users
+type Role struct {
+	Name        string `gorm:"primaryKey"`
+	Description string
+	// Permissions are stored in the role_permissions table
+	Permissions []string `gorm:"-"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RolePermission grants a permission to a role
+type RolePermission struct {
+	RoleName   string `gorm:"primaryKey"`
+	Permission string `gorm:"primaryKey"`
+}
+
+// UserRole grants a role to a user
+type UserRole struct {
+	UserID    string `gorm:"primaryKey"`
+	RoleName  string `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
+
+// DefaultRoles are created when the service starts
+var DefaultRoles = []Role{
+	{
+		Name:        auth.RoleAdmin,
+		Description: "Manages the roles and every video and channel",
+		Permissions: auth.Permissions,
+	},
+	{
+		Name:        auth.RoleModerator,
+		Description: "Moderates the videos and channels of every user",
+		Permissions: []string{
+			auth.PermVideoUpdateAny,
+			auth.PermVideoDeleteAny,
+			auth.PermVideoReadAny,
+			auth.PermChannelUpdateAny,
+			auth.PermChannelReadAny,
+		},
+	},
+}
@@ -0,0 +1,40 @@
+// Session data model
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// Session is a device a user signed in from. A login starts a session along
+// with a refresh token family, the id of the session is the id of the family.
+type Session struct {
+	ID     string `gorm:"primaryKey"`
+	UserID string `gorm:"not null;index"`
+	// UserAgent is the user agent of the login and Device its readable form
+	UserAgent string
+	Device    string
+	// DeviceHash is the sha256 of UserAgent, a login from an agent without a
+	// session of the user is announced by email
+	DeviceHash string `gorm:"index"`
+	// IP is the client address of the login, LastSeenIP the one of the last
+	// refresh
+	IP         string
+	LastSeenIP string
+	LastSeenAt time.Time
+	// ExpiresAt is the expiry of the last refresh token of the family
+	ExpiresAt time.Time `gorm:"not null;index"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Hook before create to generate the id
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.NewV4().String()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Token data models
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshToken is a single-use token exchanged for a new access token. Each
+// refresh rotates it, the tokens descending from one login share a family.
+type RefreshToken struct {
+	ID       string `gorm:"primaryKey"`
+	UserID   string `gorm:"not null;index"`
+	FamilyID string `gorm:"not null;index"`
+	// TokenHash is the sha256 of the token, the token itself is never stored
+	TokenHash string `gorm:"not null;uniqueIndex"`
+	// AccessTokenID is the jti of the access token issued with the token,
+	// revoked with the family
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+	ExpiresAt            time.Time `gorm:"not null;index"`
+	UsedAt               *time.Time
+	RevokedAt            *time.Time
+	CreatedAt            time.Time
+}
+
+// Hook before create to generate the id and start a family
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.NewV4().String()
+	}
+	if t.FamilyID == "" {
+		t.FamilyID = t.ID
+	}
+	return nil
+}
+
+// RevokedToken denies an access token until it expires
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// PasswordResetToken is a single-use token emailed to reset a password
+type PasswordResetToken struct {
+	ID     string `gorm:"primaryKey"`
+	UserID string `gorm:"not null;index"`
+	// TokenHash is the sha256 of the token, the token itself is only emailed
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Hook before create to generate the id
+func (t *PasswordResetToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.NewV4().String()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Two-factor authentication data model
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// TOTPFactor is the authenticator app of a user. The factor protects the
+// logins once confirmed with a first code.
+type TOTPFactor struct {
+	UserID string `gorm:"primaryKey"`
+	// Secret is sealed with the key of the service
+	Secret      string `gorm:"not null" json:"-"`
+	ConfirmedAt *time.Time
+	// LastStep is the time step of the last accepted code, the codes of the
+	// steps up to it are rejected
+	LastStep  int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Confirmed reports whether the factor protects the logins
+func (f *TOTPFactor) Confirmed() bool {
+	return f.ConfirmedAt != nil
+}
+
+// RecoveryCode is a single-use code replacing the authenticator app
+type RecoveryCode struct {
+	ID     string `gorm:"primaryKey"`
+	UserID string `gorm:"not null;index"`
+	// CodeHash is the sha256 of the code, the code itself is shown once
+	CodeHash  string `gorm:"not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Hook before create to generate the id
+func (c *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.NewV4().String()
+	}
+	return nil
+}
+
+// LoginChallenge is the first half of a login with two-factor
+// authentication, exchanged for the tokens along with a code
+type LoginChallenge struct {
+	// TokenHash is the sha256 of the challenge token
+	TokenHash string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	// Attempts counts the wrong codes presented with the challenge
+	Attempts  int
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -4,6 +4,7 @@ package model
 
 import (
 	"regexp"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -29,18 +30,42 @@ type User struct {
 	LastName  string `gorm:"not null" json:"last_name"`
 	Username  string `gorm:"uniqueIndex" json:"username"`
 	Email     string `gorm:"uniqueIndex" json:"email"`
-	Phone     string `gorm:"uniqueIndex" json:"phone"`
-	Password  string `gorm:"not null" json:"-"`
+	// Phone is empty for the users signed up with an external provider, the
+	// index only covers the set numbers
+	Phone string `gorm:"uniqueIndex:idx_users_set_phone,where:phone <> ''" json:"phone"`
+	// Password is empty for the users signed up with an external provider
+	// until they reset it
+	Password string `gorm:"not null" json:"-"`
+	// EmailVerifiedAt is nil until the user opens the verification link
+	// sent to Email
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// EmailVerified reports whether the user verified their email address
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 // Hook before create to generate uuid and hash password
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	uuid := uuid.NewV4()
 	u.UUID = uuid.String()
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if u.Password == "" {
+		return nil
+	}
+	hashedPassword, err := HashPassword(u.Password)
 	if err != nil {
 		return err
 	}
-	u.Password = string(hashedPassword)
+	u.Password = hashedPassword
 	return nil
 }
+
+// HashPassword returns the bcrypt hash stored for a password
+func HashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
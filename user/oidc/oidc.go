@@ -0,0 +1,223 @@
+// Package oidc is the relying party of the logins with external OpenID
+// Connect providers: the authorization code flow with PKCE and the
+// verification of the id tokens against the key set of the provider.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/jwks"
+)
+
+// keySetTTL is how long the key set of a provider is trusted before it is
+// fetched again, unknown key ids refresh it sooner
+const keySetTTL = time.Hour
+
+// httpTimeout bounds the calls to the providers
+const httpTimeout = 10 * time.Second
+
+// maxResponseSize bounds the documents read from the providers
+const maxResponseSize = 1 << 20
+
+// DefaultScopes are requested from the providers without configured scopes
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// ErrExchange is returned when the provider refuses the authorization code
+var ErrExchange = errors.New("the provider rejected the authorization code")
+
+// ErrUnavailable is returned when the provider cannot be reached or answers
+// with malformed documents
+var ErrUnavailable = errors.New("the identity provider is unavailable")
+
+// Config describes the registration of the service at a provider
+type Config struct {
+	// Name identifies the provider in the routes and the linked identities
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback registered at the provider
+	RedirectURL string
+	Scopes      []string
+}
+
+// discovery is the part of the provider metadata used by the relying party,
+// see https://openid.net/specs/openid-connect-discovery-1_0.html
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect provider. Its metadata is discovered on first
+// use and kept, a failed discovery is retried by the next call.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *jwks.Cache
+}
+
+// NewProvider returns the provider of the config, the default client is used
+// when client is nil
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	return &Provider{config: config, client: client}
+}
+
+// Name returns the name of the provider
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns the authorization endpoint the user is sent to, the
+// provider redirects back with the state and a code bound to the nonce and to
+// the challenge of the verifier. The login hint, usually an email address, is
+// passed on when set.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string, loginHint string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	endpoint, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", ErrUnavailable
+	}
+	query := endpoint.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	if loginHint != "" {
+		query.Set("login_hint", loginHint)
+	}
+	endpoint.RawQuery = query.Encode()
+	return endpoint.String(), nil
+}
+
+// tokenResponse is the part of the token endpoint response used by the
+// relying party, the access token of the provider is not kept
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+// Exchange trades an authorization code and the verifier of its challenge for
+// the id token of the user
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic, the credentials are form encoded first
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer res.Body.Close()
+	body := &tokenResponse{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(body); err != nil {
+		return "", fmt.Errorf("%w: malformed token response", ErrUnavailable)
+	}
+	if res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("%w: %s", ErrExchange, body.Error)
+	}
+	if res.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("%w: token endpoint answered %d", ErrUnavailable, res.StatusCode)
+	}
+	return body.IDToken, nil
+}
+
+// metadata returns the discovered metadata of the provider
+func (p *Provider) metadata(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	meta := &discovery{}
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", meta); err != nil {
+		return nil, err
+	}
+	// the metadata of another issuer would let it sign the tokens
+	if meta.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("%w: discovered issuer %q", ErrUnavailable, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata", ErrUnavailable)
+	}
+	p.discovery = meta
+	p.keys = jwks.NewCache(func(ctx context.Context) (*jwks.Set, error) {
+		set := &jwks.Set{}
+		if err := p.getJSON(ctx, meta.JWKSURI, set); err != nil {
+			return nil, err
+		}
+		return set, nil
+	}, keySetTTL)
+	return meta, nil
+}
+
+// keySet returns the key set cache of the provider
+func (p *Provider) keySet(ctx context.Context) (*jwks.Cache, error) {
+	if _, err := p.metadata(ctx); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.keys, nil
+}
+
+// getJSON decodes the document at the url into v
+func (p *Provider) getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s answered %d", ErrUnavailable, rawURL, res.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: malformed %s", ErrUnavailable, rawURL)
+	}
+	return nil
+}
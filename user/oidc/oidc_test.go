@@ -0,0 +1,234 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/iamvasanth07/showcase/user/oidc"
+	"github.com/iamvasanth07/showcase/user/oidc/oidctest"
+)
+
+const (
+	clientID     = "showcase"
+	clientSecret = "showcase-secret"
+	redirectURL  = "http://localhost:8080/api/v1/user/oidc/mock/callback"
+)
+
+// login is an authorization approved by the mock provider
+type login struct {
+	nonce    string
+	verifier string
+	code     string
+}
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Provider) {
+	t.Helper()
+	server, mock, err := oidctest.NewServer(clientID, clientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	}, server.Client())
+	return provider, mock
+}
+
+// authorize starts a login of the user with the email and returns the code
+// the provider redirected back with
+func authorize(t *testing.T, provider *oidc.Provider, email string) *login {
+	t.Helper()
+	l := &login{}
+	var state string
+	for _, value := range []*string{&state, &l.nonce, &l.verifier} {
+		random, err := oidc.NewRandom()
+		if err != nil {
+			t.Fatal(err)
+		}
+		*value = random
+	}
+	authURL, err := provider.AuthCodeURL(context.Background(), state, l.nonce, l.verifier, email)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("authorization answered %d", res.StatusCode)
+	}
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(location.String(), redirectURL) {
+		t.Fatalf("redirected to %s, want the callback", location)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("redirected with state %q, want %q", got, state)
+	}
+	l.code = location.Query().Get("code")
+	return l
+}
+
+func TestLogin(t *testing.T) {
+	provider, _ := newProvider(t)
+	l := authorize(t, provider, "alice@example.com")
+	idToken, err := provider.Exchange(context.Background(), l.code, l.verifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	claims, err := provider.VerifyIDToken(context.Background(), idToken, l.nonce)
+	if err != nil {
+		t.Fatalf("VerifyIDToken() error = %v", err)
+	}
+	if claims.Email != "alice@example.com" || !bool(claims.EmailVerified) || claims.Subject == "" {
+		t.Errorf("claims = %+v, want the verified address of alice", claims)
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange func(provider *oidc.Provider, l *login) error
+	}{
+		{
+			name: "pkce verifier mismatch",
+			exchange: func(provider *oidc.Provider, l *login) error {
+				_, err := provider.Exchange(context.Background(), l.code, l.verifier+"x")
+				return err
+			},
+		},
+		{
+			name: "unknown code",
+			exchange: func(provider *oidc.Provider, l *login) error {
+				_, err := provider.Exchange(context.Background(), "unknown", l.verifier)
+				return err
+			},
+		},
+		{
+			name: "replayed code",
+			exchange: func(provider *oidc.Provider, l *login) error {
+				if _, err := provider.Exchange(context.Background(), l.code, l.verifier); err != nil {
+					return err
+				}
+				_, err := provider.Exchange(context.Background(), l.code, l.verifier)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := newProvider(t)
+			l := authorize(t, provider, "alice@example.com")
+			if err := tt.exchange(provider, l); !errors.Is(err, oidc.ErrExchange) {
+				t.Errorf("Exchange() error = %v, want %v", err, oidc.ErrExchange)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		// sign returns the id token presented for the login
+		sign func(t *testing.T, mock *oidctest.Provider, l *login) string
+	}{
+		{
+			name: "nonce mismatch",
+			sign: func(t *testing.T, mock *oidctest.Provider, l *login) string {
+				return signIDToken(t, mock, "another nonce")
+			},
+		},
+		{
+			name: "empty nonce",
+			sign: func(t *testing.T, mock *oidctest.Provider, l *login) string {
+				return signIDToken(t, mock, "")
+			},
+		},
+		{
+			name: "other audience",
+			sign: func(t *testing.T, mock *oidctest.Provider, l *login) string {
+				mock.ClientID = "another-client"
+				defer func() { mock.ClientID = clientID }()
+				return signIDToken(t, mock, l.nonce)
+			},
+		},
+		{
+			name: "other issuer",
+			sign: func(t *testing.T, mock *oidctest.Provider, l *login) string {
+				issuer := mock.Issuer
+				mock.Issuer = "https://attacker.example.com"
+				defer func() { mock.Issuer = issuer }()
+				return signIDToken(t, mock, l.nonce)
+			},
+		},
+		{
+			name: "tampered signature",
+			sign: func(t *testing.T, mock *oidctest.Provider, l *login) string {
+				token := signIDToken(t, mock, l.nonce)
+				dot := strings.LastIndex(token, ".")
+				return token[:dot+1] + strings.Repeat("A", len(token)-dot-1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, mock := newProvider(t)
+			l := authorize(t, provider, "alice@example.com")
+			_, err := provider.VerifyIDToken(context.Background(), tt.sign(t, mock, l), l.nonce)
+			if !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("VerifyIDToken() error = %v, want %v", err, oidc.ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestUnverifiedEmail(t *testing.T) {
+	provider, mock := newProvider(t)
+	mock.EmailVerified = false
+	l := authorize(t, provider, "alice@example.com")
+	idToken, err := provider.Exchange(context.Background(), l.code, l.verifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	claims, err := provider.VerifyIDToken(context.Background(), idToken, l.nonce)
+	if err != nil {
+		t.Fatalf("VerifyIDToken() error = %v", err)
+	}
+	if claims.EmailVerified {
+		t.Error("EmailVerified = true, want false")
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	provider, mock := newProvider(t)
+	// the metadata served at the issuer names another issuer
+	mock.Issuer = "https://attacker.example.com"
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier", "")
+	if !errors.Is(err, oidc.ErrUnavailable) {
+		t.Errorf("AuthCodeURL() error = %v, want %v", err, oidc.ErrUnavailable)
+	}
+}
+
+func signIDToken(t *testing.T, mock *oidctest.Provider, nonce string) string {
+	t.Helper()
+	token, err := mock.SignIDToken("alice@example.com", nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
@@ -0,0 +1,264 @@
+// Package oidctest is a local OpenID Connect provider for trying the external
+// logins without registering the service at a real provider. It approves
+// every authorization request at once, the user is taken from the login_hint
+// parameter.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/iamvasanth07/showcase/common/jwks"
+)
+
+// DefaultEmail is the user of the authorization requests without a login_hint
+const DefaultEmail = "mock.user@example.com"
+
+// codeExpiry is the lifetime of the authorization codes
+const codeExpiry = time.Minute
+
+// tokenExpiry is the lifetime of the id tokens
+const tokenExpiry = 5 * time.Minute
+
+// keyID names the signing key in the key set
+const keyID = "mock"
+
+// authorization is a pending authorization code
+type authorization struct {
+	email       string
+	nonce       string
+	redirectURI string
+	challenge   string
+	expiresAt   time.Time
+}
+
+// Provider is the mock provider, an http.Handler serving the discovery
+// document, the authorization and token endpoints and the key set
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// EmailVerified is the email_verified claim of the tokens
+	EmailVerified bool
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]*authorization
+}
+
+// New returns a provider answering as the issuer to the client
+func New(issuer string, clientID string, clientSecret string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		Issuer:        strings.TrimSuffix(issuer, "/"),
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		EmailVerified: true,
+		key:           key,
+		codes:         make(map[string]*authorization),
+	}, nil
+}
+
+// NewServer starts a provider on a local port, the issuer is the url of the
+// server. The caller closes the server.
+func NewServer(clientID string, clientSecret string) (*httptest.Server, *Provider, error) {
+	provider, err := New("", clientID, clientSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := httptest.NewServer(provider)
+	provider.Issuer = server.URL
+	return server, provider, nil
+}
+
+// ServeHTTP serves the endpoints of the provider
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		p.discovery(w)
+	case "/authorize":
+		p.authorize(w, r)
+	case "/token":
+		p.token(w, r)
+	case "/jwks":
+		p.keySet(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *Provider) discovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer,
+		"authorization_endpoint":                p.Issuer + "/authorize",
+		"token_endpoint":                        p.Issuer + "/token",
+		"jwks_uri":                              p.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{jwks.AlgRS256},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize approves the request and redirects back with a code
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case query.Get("client_id") != p.ClientID:
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	case query.Get("response_type") != "code":
+		http.Error(w, "unsupported response_type", http.StatusBadRequest)
+		return
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		http.Error(w, "missing S256 code_challenge", http.StatusBadRequest)
+		return
+	}
+	email := query.Get("login_hint")
+	if email == "" {
+		email = DefaultEmail
+	}
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = &authorization{
+		email:       email,
+		nonce:       query.Get("nonce"),
+		redirectURI: redirectURI.String(),
+		challenge:   query.Get("code_challenge"),
+		expiresAt:   time.Now().Add(codeExpiry),
+	}
+	p.mu.Unlock()
+	back := redirectURI.Query()
+	back.Set("code", code)
+	back.Set("state", query.Get("state"))
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token exchanges a code for an id token
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.ClientSecret)) != 1 {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+	// the codes are single use, even when the exchange fails
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case auth == nil || time.Now().After(auth.expiresAt):
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	case r.PostForm.Get("redirect_uri") != auth.redirectURI:
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge:
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	idToken, err := p.SignIDToken(auth.email, auth.nonce)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenExpiry.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// SignIDToken returns an id token of the user with the email, the subject is
+// derived from the email
+func (p *Provider) SignIDToken(email string, nonce string) (string, error) {
+	now := time.Now()
+	name := strings.SplitN(email, "@", 2)[0]
+	subject := sha256.Sum256([]byte(email))
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.Issuer,
+		"sub":            hex.EncodeToString(subject[:8]),
+		"aud":            p.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(tokenExpiry).Unix(),
+		"nonce":          nonce,
+		"email":          email,
+		"email_verified": p.EmailVerified,
+		"name":           "Mock " + name,
+		"given_name":     "Mock",
+		"family_name":    name,
+	})
+	token.Header["kid"] = keyID
+	return token.SignedString(p.key)
+}
+
+func (p *Provider) keySet(w http.ResponseWriter) {
+	public := &p.key.PublicKey
+	writeJSON(w, http.StatusOK, &jwks.Set{Keys: []jwks.Key{{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwks.AlgRS256,
+		Kid: keyID,
+		N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}}})
+}
+
+func tokenError(w http.ResponseWriter, code int, err string) {
+	writeJSON(w, code, map[string]string{"error": err})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when no user matches the query
+var ErrNotFound = errors.New("user not found")
+
+// ErrInvalidCursor is returned for cursors with a malformed key
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrRoleNotFound is returned for roles that do not exist
+var ErrRoleNotFound = errors.New("role not found")
+
+// ErrLastAdmin is returned when revoking the admin role from the last admin
+// or deleting the last admin
+var ErrLastAdmin = errors.New("the last admin cannot lose the admin role")
+
+// ErrTokenReused is returned for refresh tokens presented after their rotation
+var ErrTokenReused = errors.New("refresh token reuse detected")
+
+// ErrTokenExpired is returned for expired refresh tokens
+var ErrTokenExpired = errors.New("refresh token expired")
+
+// ErrInvalidResetToken is returned for reset tokens that are unknown, used or expired
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+// ErrInvalidVerificationToken is returned for verification tokens that do not
+// match the current email address of their user
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+// ErrRateLimited is returned when a user asks for emails too often
+var ErrRateLimited = errors.New("too many emails requested, try again later")
+
+// ErrTwoFactorNotEnabled is returned for users without a confirmed authenticator app
+var ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+
+// ErrTwoFactorEnabled is returned when enrolling a user whose authenticator app is confirmed
+var ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+
+// ErrInvalidCode is returned for wrong, used or replayed second factor codes
+var ErrInvalidCode = errors.New("invalid code")
+
+// ErrInvalidChallenge is returned for login challenges that are unknown,
+// used, expired or failed too many times
+var ErrInvalidChallenge = errors.New("invalid or expired login challenge")
+
+// ErrAccessTokenNotFound is returned for personal access tokens that do not
+// exist or belong to another user
+var ErrAccessTokenNotFound = errors.New("personal access token not found")
+
+// ErrInvalidAccessToken is returned for personal access tokens that are
+// unknown, revoked or expired
+var ErrInvalidAccessToken = errors.New("invalid or expired personal access token")
+
+// ErrSessionNotFound is returned for sessions that are not active or belong
+// to another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrInvalidLoginState is returned for external login states that are
+// unknown, used, expired or issued for another provider
+var ErrInvalidLoginState = errors.New("invalid or expired login state")
+
+// ErrIdentityNotLinked is returned for provider accounts linked to no user
+var ErrIdentityNotLinked = errors.New("external identity not linked")
+
+// unique_violation, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const uniqueViolation = "23505"
+
+// DuplicateError is returned when a unique column already holds the value
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s already exists", e.Field)
+}
+
+// translate converts gorm and postgres errors into repo errors
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return &DuplicateError{Field: fieldFromConstraint(pgErr.ConstraintName)}
+	}
+	return err
+}
+
+// fieldFromConstraint extracts the column from gorm's idx_<table>_<column> index names
+func fieldFromConstraint(constraint string) string {
+	for _, field := range []string{"email", "username", "phone"} {
+		if strings.HasSuffix(constraint, "_"+field) {
+			return field
+		}
+	}
+	return constraint
+}
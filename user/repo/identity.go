@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateOIDCLogin stores a login started at a provider
+func (r *UserRepo) CreateOIDCLogin(login *model.OIDCLogin) error {
+	return translate(r.db.Create(login).Error)
+}
+
+// ConsumeOIDCLogin removes and returns the pending login of the provider with
+// the state hash, a state is accepted once even when it is presented to
+// another provider
+func (r *UserRepo) ConsumeOIDCLogin(hash string, provider string, now time.Time) (*model.OIDCLogin, error) {
+	login := &model.OIDCLogin{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("state_hash = ?", hash).First(login).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(login).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidLoginState
+	}
+	if err != nil {
+		return nil, translate(err)
+	}
+	if login.Provider != provider || !login.ExpiresAt.After(now) {
+		return nil, ErrInvalidLoginState
+	}
+	return login, nil
+}
+
+// DeleteExpiredOIDCLogins removes the logins that were never completed
+func (r *UserRepo) DeleteExpiredOIDCLogins(now time.Time) error {
+	return translate(r.db.Where("expires_at < ?", now).Delete(&model.OIDCLogin{}).Error)
+}
+
+// FindExternalIdentity returns the identity of the provider account
+func (r *UserRepo) FindExternalIdentity(provider string, subject string) (*model.ExternalIdentity, error) {
+	identity := &model.ExternalIdentity{}
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrIdentityNotLinked
+	}
+	if err != nil {
+		return nil, translate(err)
+	}
+	return identity, nil
+}
+
+// LinkExternalIdentity links a provider account to an existing user
+func (r *UserRepo) LinkExternalIdentity(identity *model.ExternalIdentity) error {
+	return translate(r.db.Create(identity).Error)
+}
+
+// CreateExternalUser creates a user along with the provider account it signed
+// up with
+func (r *UserRepo) CreateExternalUser(user *model.User, identity *model.ExternalIdentity) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.UUID
+		return tx.Create(identity).Error
+	}))
+}
+
+// TouchExternalIdentity records a login with the provider account
+func (r *UserRepo) TouchExternalIdentity(identity *model.ExternalIdentity, email string, now time.Time) error {
+	return translate(r.db.Model(identity).Updates(map[string]interface{}{"email": email, "last_login_at": now}).Error)
+}
+
+// DeleteExternalIdentity unlinks a provider account
+func (r *UserRepo) DeleteExternalIdentity(identity *model.ExternalIdentity) error {
+	return translate(r.db.Delete(identity).Error)
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ThrottlePolicy locks a throttle after MaxFailures failed logins within
+// Window, for Base doubled by every further failure up to Max
+type ThrottlePolicy struct {
+	MaxFailures int
+	Window      time.Duration
+	Base        time.Duration
+	Max         time.Duration
+}
+
+// Lockout returns the lock duration after the given number of failures
+func (p ThrottlePolicy) Lockout(failures int) time.Duration {
+	if failures < p.MaxFailures {
+		return 0
+	}
+	shift := failures - p.MaxFailures
+	if shift > 30 || p.Base<<shift > p.Max {
+		return p.Max
+	}
+	return p.Base << shift
+}
+
+// LockedUntil returns the latest end of the locks of the keys, the zero time
+// when none of them is locked at now
+func (r *UserRepo) LockedUntil(keys []string, now time.Time) (time.Time, error) {
+	var throttles []model.LoginThrottle
+	err := r.db.Where("key IN ? AND locked_until > ?", keys, now).Find(&throttles).Error
+	if err != nil {
+		return time.Time{}, translate(err)
+	}
+	var until time.Time
+	for _, throttle := range throttles {
+		if throttle.LockedUntil.After(until) {
+			until = *throttle.LockedUntil
+		}
+	}
+	return until, nil
+}
+
+// RecordLoginFailure counts a failed login of the key and locks it once it
+// reaches the limit of the policy, recording the lock in the audit events.
+// It returns the throttle.
+func (r *UserRepo) RecordLoginFailure(key string, policy ThrottlePolicy, event *model.AuditEvent, now time.Time) (*model.LoginThrottle, error) {
+	throttle := &model.LoginThrottle{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.LoginThrottle{Key: key, LastFailureAt: now}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(throttle).Error
+		if err != nil {
+			return err
+		}
+		// the failures older than the window are forgiven
+		if now.Sub(throttle.LastFailureAt) > policy.Window {
+			throttle.Failures = 0
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = now
+		if lockout := policy.Lockout(throttle.Failures); lockout > 0 {
+			until := now.Add(lockout)
+			throttle.LockedUntil = &until
+			event.Event = model.AuditLocked
+			event.Key = key
+			event.Failures = throttle.Failures
+			event.LockedUntil = &until
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(throttle).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return throttle, nil
+}
+
+// ResetLoginFailures forgets the failed logins of the key
+func (r *UserRepo) ResetLoginFailures(key string) error {
+	return translate(r.db.Where("key = ?", key).Delete(&model.LoginThrottle{}).Error)
+}
+
+// Unlock lifts the lock of the key and records it in the audit events
+func (r *UserRepo) Unlock(key string, event *model.AuditEvent) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("key = ?", key).Delete(&model.LoginThrottle{}).Error; err != nil {
+			return err
+		}
+		event.Event = model.AuditUnlocked
+		event.Key = key
+		return tx.Create(event).Error
+	}))
+}
+
+// DeleteStaleThrottles removes the throttles without failures since the
+// given time that are no longer locked
+func (r *UserRepo) DeleteStaleThrottles(before time.Time, now time.Time) error {
+	return translate(r.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, now).
+		Delete(&model.LoginThrottle{}).Error)
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClaimEmails returns the pending emails due at now, at most limit of them,
+// and counts their attempt. Their next attempt is pushed back by lease so
+// that the other instances of the service leave them alone while they are
+// sent, outside of any transaction.
+func (r *UserRepo) ClaimEmails(now time.Time, limit int, maxAttempts int, lease time.Duration) ([]model.OutboxEmail, error) {
+	var emails []model.OutboxEmail
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND attempts < ? AND next_attempt_at <= ?", maxAttempts, now).
+			Order("next_attempt_at").Limit(limit).Find(&emails).Error
+		if err != nil || len(emails) == 0 {
+			return err
+		}
+		ids := make([]string, len(emails))
+		for i := range emails {
+			ids[i] = emails[i].ID
+			emails[i].Attempts++
+			emails[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&model.OutboxEmail{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return emails, nil
+}
+
+// MarkEmailSent records the delivery of a claimed email. Its body is dropped,
+// it may carry a token.
+func (r *UserRepo) MarkEmailSent(email *model.OutboxEmail, now time.Time) error {
+	return translate(r.db.Model(email).Updates(map[string]interface{}{
+		"sent_at":    now,
+		"last_error": "",
+		"body":       "",
+	}).Error)
+}
+
+// MarkEmailFailed records the failed attempt of a claimed email, retried at
+// next. The body of an email out of attempts is dropped.
+func (r *UserRepo) MarkEmailFailed(email *model.OutboxEmail, cause string, next time.Time, maxAttempts int) error {
+	fields := map[string]interface{}{
+		"last_error":      cause,
+		"next_attempt_at": next,
+	}
+	if email.Attempts >= maxAttempts {
+		fields["body"] = ""
+	}
+	return translate(r.db.Model(email).Updates(fields).Error)
+}
+
+// DeleteSentEmails removes the emails sent before the given time
+func (r *UserRepo) DeleteSentEmails(before time.Time) error {
+	return translate(r.db.Where("sent_at < ?", before).Delete(&model.OutboxEmail{}).Error)
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreatePasswordReset stores a reset token along with the email carrying it,
+// unless the user went over the limit of the reset emails. The previous
+// tokens of the user can no longer be used.
+func (r *UserRepo) CreatePasswordReset(token *model.PasswordResetToken, email *model.OutboxEmail, limit EmailLimit, now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmailLimit(tx, email, limit, now); err != nil {
+			return err
+		}
+		err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(token).Error; err != nil {
+			return err
+		}
+		return tx.Create(email).Error
+	}))
+}
+
+// ResetPassword consumes the reset token with the hash, replaces the password
+// hash of its user and revokes the refresh tokens and the personal access
+// tokens of the user. It returns the id of the user.
+func (r *UserRepo) ResetPassword(hash string, passwordHash string, now time.Time) (string, error) {
+	token := &model.PasswordResetToken{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", hash).First(token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+		if token.UsedAt != nil || now.After(token.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+		if err := tx.Model(token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&model.User{}).Where("uuid = ?", token.UserID).Update("password", passwordHash)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+		if err := revokeUserTokens(tx, token.UserID, now); err != nil {
+			return err
+		}
+		return revokeAccessTokens(tx, token.UserID, now)
+	})
+	if err != nil {
+		return "", translate(err)
+	}
+	return token.UserID, nil
+}
+
+// DeleteExpiredResetTokens removes the reset tokens that expired
+func (r *UserRepo) DeleteExpiredResetTokens(now time.Time) error {
+	return translate(r.db.Where("expires_at < ?", now).Delete(&model.PasswordResetToken{}).Error)
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+)
+
+// CreatePersonalAccessToken stores a personal access token
+func (r *UserRepo) CreatePersonalAccessToken(token *model.PersonalAccessToken) error {
+	return translate(r.db.Create(token).Error)
+}
+
+// ListPersonalAccessTokens returns the unrevoked personal access tokens of
+// the user, newest first
+func (r *UserRepo) ListPersonalAccessTokens(userID string) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return tokens, nil
+}
+
+// RevokePersonalAccessToken revokes the personal access token of the user
+func (r *UserRepo) RevokePersonalAccessToken(id string, userID string, now time.Time) error {
+	res := r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now)
+	if res.Error != nil {
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// UsePersonalAccessToken returns the valid personal access token with the
+// hash and records its use from the client address. The use is only written
+// when the address changes or once per usedGranularity so that busy tokens
+// do not update their row on every request.
+func (r *UserRepo) UsePersonalAccessToken(hash string, ip string, now time.Time, usedGranularity time.Duration) (*model.PersonalAccessToken, error) {
+	token := &model.PersonalAccessToken{}
+	err := r.db.Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", hash, now).First(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, translate(err)
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= usedGranularity || token.LastUsedIP != ip {
+		err := r.db.Model(token).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+		if err != nil {
+			return nil, translate(err)
+		}
+	}
+	return token, nil
+}
+
+// revokeAccessTokens revokes every personal access token of a user
+func revokeAccessTokens(tx *gorm.DB, userID string, now time.Time) error {
+	return tx.Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+// DeleteStaleAccessTokens removes the personal access tokens revoked or
+// expired before the given time
+func (r *UserRepo) DeleteStaleAccessTokens(before time.Time) error {
+	return translate(r.db.Where("revoked_at < ? OR expires_at < ?", before, before).
+		Delete(&model.PersonalAccessToken{}).Error)
+}
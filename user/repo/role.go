@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"errors"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnsureRoles creates the missing roles and grants them the missing
+// permissions, the permissions granted since are kept
+func (r *UserRepo) EnsureRoles(roles []model.Role) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range roles {
+			role := roles[i]
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&role).Error; err != nil {
+				return err
+			}
+			for _, permission := range role.Permissions {
+				grant := &model.RolePermission{RoleName: role.Name, Permission: permission}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(grant).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}))
+}
+
+// ListRoles returns every role with its permissions
+func (r *UserRepo) ListRoles() ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.db.Order("name").Find(&roles).Error; err != nil {
+		return nil, translate(err)
+	}
+	return r.withPermissions(roles)
+}
+
+// FindUserRoles returns the roles granted to a user with their permissions
+func (r *UserRepo) FindUserRoles(userID string) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.Joins("JOIN user_roles ON user_roles.role_name = roles.name").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").Find(&roles).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return r.withPermissions(roles)
+}
+
+// withPermissions loads the permissions of the roles
+func (r *UserRepo) withPermissions(roles []model.Role) ([]model.Role, error) {
+	if len(roles) == 0 {
+		return roles, nil
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	var grants []model.RolePermission
+	err := r.db.Where("role_name IN ?", names).Order("role_name, permission").Find(&grants).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	byRole := make(map[string][]string, len(roles))
+	for _, grant := range grants {
+		byRole[grant.RoleName] = append(byRole[grant.RoleName], grant.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = byRole[roles[i].Name]
+	}
+	return roles, nil
+}
+
+// AssignRole grants a role to a user, granting it twice is not an error
+func (r *UserRepo) AssignRole(userID string, roleName string) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.Role{}, "name = ?", roleName).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if err := tx.Where("uuid = ?", userID).First(&model.User{}).Error; err != nil {
+			return err
+		}
+		grant := &model.UserRole{UserID: userID, RoleName: roleName}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(grant).Error
+	}))
+}
+
+// RevokeRole takes a role back from a user. The last admin keeps the admin
+// role so that the roles can still be managed.
+func (r *UserRepo) RevokeRole(userID string, roleName string) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		// the role row serializes the concurrent revocations
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Role{}, "name = ?", roleName).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if roleName == auth.RoleAdmin {
+			if err := requireOtherAdmin(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Where("user_id = ? AND role_name = ?", userID, roleName).Delete(&model.UserRole{}).Error
+	}))
+}
+
+// requireOtherAdmin fails with ErrLastAdmin unless a user other than userID
+// holds the admin role. The caller locks the admin role row first.
+func requireOtherAdmin(tx *gorm.DB, userID string) error {
+	var admins int64
+	err := roleMembers(tx, auth.RoleAdmin).Where("user_roles.user_id <> ?", userID).Count(&admins).Error
+	if err != nil {
+		return err
+	}
+	if admins == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// roleMembers selects the grants of a role to the users not deleted
+func roleMembers(db *gorm.DB, roleName string) *gorm.DB {
+	return db.Model(&model.UserRole{}).
+		Joins("JOIN users ON users.uuid = user_roles.user_id AND users.deleted_at IS NULL").
+		Where("user_roles.role_name = ?", roleName)
+}
+
+// CountRoleMembers returns the number of users holding a role
+func (r *UserRepo) CountRoleMembers(roleName string) (int64, error) {
+	var count int64
+	err := roleMembers(r.db, roleName).Count(&count).Error
+	return count, translate(err)
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+)
+
+// CreateSession stores the session of a login along with the first refresh
+// token of its family. A session from a device the user never signed in from
+// is announced with the email returned by notify, the first session of a user
+// is not.
+func (r *UserRepo) CreateSession(session *model.Session, refresh *model.RefreshToken, notify func(*model.Session) *model.OutboxEmail) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		var sessions, known int64
+		if err := tx.Model(&model.Session{}).Where("user_id = ?", session.UserID).Count(&sessions).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&model.Session{}).Where("user_id = ? AND device_hash = ?", session.UserID, session.DeviceHash).
+			Count(&known).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		refresh.UserID = session.UserID
+		refresh.FamilyID = session.ID
+		if err := tx.Create(refresh).Error; err != nil {
+			return err
+		}
+		if sessions == 0 || known > 0 {
+			return nil
+		}
+		if email := notify(session); email != nil {
+			return tx.Create(email).Error
+		}
+		return nil
+	}))
+}
+
+// TouchSession records a refresh of the session
+func (r *UserRepo) TouchSession(id string, ip string, expiresAt time.Time, now time.Time) error {
+	return translate(r.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"last_seen_at": now, "last_seen_ip": ip, "expires_at": expiresAt}).Error)
+}
+
+// FindSessionID returns the id of the session of the access token with the
+// jti, empty when the token belongs to no session
+func (r *UserRepo) FindSessionID(jti string) (string, error) {
+	token := &model.RefreshToken{}
+	err := r.db.Select("family_id").Where("access_token_id = ?", jti).First(token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", translate(err)
+	}
+	return token.FamilyID, nil
+}
+
+// ListSessions returns the active sessions of the user, the most recently
+// seen first
+func (r *UserRepo) ListSessions(userID string, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes the active session of the user along with its tokens
+func (r *UserRepo) RevokeSession(id string, userID string, now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		session := &model.Session{}
+		err := tx.Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", id, userID, now).
+			First(session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return revokeFamily(tx, session.ID, now)
+	}))
+}
+
+// RevokeOtherSessions revokes the tokens of the user except the ones of the
+// kept session, personal access tokens included, and returns the number of
+// revoked sessions
+func (r *UserRepo) RevokeOtherSessions(userID string, keepID string, now time.Time) (int, error) {
+	var families []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL AND expires_at > ?", userID, keepID, now).
+			Distinct().Pluck("family_id", &families).Error
+		if err != nil {
+			return err
+		}
+		for _, family := range families {
+			if err := revokeFamily(tx, family, now); err != nil {
+				return err
+			}
+		}
+		return revokeAccessTokens(tx, userID, now)
+	})
+	if err != nil {
+		return 0, translate(err)
+	}
+	return len(families), nil
+}
+
+// DeleteEndedSessions removes the sessions revoked or expired before the
+// given time, their devices are no longer known
+func (r *UserRepo) DeleteEndedSessions(before time.Time) error {
+	return translate(r.db.Where("revoked_at < ? OR expires_at < ?", before, before).Delete(&model.Session{}).Error)
+}
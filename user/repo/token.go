@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RotateRefreshToken marks the token with the hash as used and stores next
+// in its family, returning the used token. A token presented after it was
+// used or revoked revokes its whole family and returns ErrTokenReused.
+func (r *UserRepo) RotateRefreshToken(hash string, next *model.RefreshToken, now time.Time) (*model.RefreshToken, error) {
+	current := &model.RefreshToken{}
+	reused := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", hash).First(current).Error
+		if err != nil {
+			return err
+		}
+		if current.UsedAt != nil || current.RevokedAt != nil {
+			reused = true
+			return revokeFamily(tx, current.FamilyID, now)
+		}
+		if now.After(current.ExpiresAt) {
+			return ErrTokenExpired
+		}
+		if err := tx.Model(current).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		next.UserID = current.UserID
+		next.FamilyID = current.FamilyID
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	if reused {
+		return nil, ErrTokenReused
+	}
+	return current, nil
+}
+
+// RevokeRefreshFamily revokes the family of the refresh token of a user
+func (r *UserRepo) RevokeRefreshFamily(hash string, userID string, now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		token := &model.RefreshToken{}
+		if err := tx.Where("token_hash = ? AND user_id = ?", hash, userID).First(token).Error; err != nil {
+			return err
+		}
+		return revokeFamily(tx, token.FamilyID, now)
+	}))
+}
+
+// revokeFamily revokes the refresh tokens of a family and its session and
+// denies the access tokens issued with them
+func revokeFamily(tx *gorm.DB, familyID string, now time.Time) error {
+	var tokens []model.RefreshToken
+	err := tx.Where("family_id = ? AND access_token_id <> '' AND access_token_expires_at > ?", familyID, now).
+		Find(&tokens).Error
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err := denyAccessToken(tx, token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+			return err
+		}
+	}
+	err = tx.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+// revokeUserTokens revokes every refresh token family of a user
+func revokeUserTokens(tx *gorm.DB, userID string, now time.Time) error {
+	var families []string
+	err := tx.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Distinct().Pluck("family_id", &families).Error
+	if err != nil {
+		return err
+	}
+	for _, family := range families {
+		if err := revokeFamily(tx, family, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeAccessToken denies the access token with the jti until it expires
+func (r *UserRepo) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	return translate(denyAccessToken(r.db, jti, expiresAt))
+}
+
+func denyAccessToken(tx *gorm.DB, jti string, expiresAt time.Time) error {
+	revoked := &model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error
+}
+
+// ListRevokedTokens returns the unexpired access tokens revoked since the given time
+func (r *UserRepo) ListRevokedTokens(since time.Time, now time.Time) ([]model.RevokedToken, error) {
+	var tokens []model.RevokedToken
+	err := r.db.Where("created_at >= ? AND expires_at > ?", since, now).Order("created_at").Find(&tokens).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return tokens, nil
+}
+
+// DeleteExpiredTokens removes the refresh tokens and the denied access
+// tokens that expired
+func (r *UserRepo) DeleteExpiredTokens(now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", now).Delete(&model.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", now).Delete(&model.RevokedToken{}).Error
+	}))
+}
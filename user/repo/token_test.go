@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+)
+
+// refreshToken returns a refresh token issued with the access token jti
+func refreshToken(hash string, jti string, now time.Time) *model.RefreshToken {
+	return &model.RefreshToken{
+		TokenHash:            hash,
+		AccessTokenID:        jti,
+		AccessTokenExpiresAt: now.Add(15 * time.Minute),
+		ExpiresAt:            now.Add(24 * time.Hour),
+	}
+}
+
+func TestRotateRefreshTokenRevokesReusedFamilies(t *testing.T) {
+	db := testDB(t, &model.Session{}, &model.RefreshToken{}, &model.RevokedToken{}, &model.OutboxEmail{})
+	r := NewUserRepo(db)
+	now := time.Now()
+	noEmail := func(*model.Session) *model.OutboxEmail { return nil }
+
+	session := &model.Session{UserID: "user-1", ExpiresAt: now.Add(24 * time.Hour)}
+	if err := r.CreateSession(session, refreshToken("hash-1", "jti-1", now), noEmail); err != nil {
+		t.Fatal(err)
+	}
+	other := &model.Session{UserID: "user-1", ExpiresAt: now.Add(24 * time.Hour)}
+	if err := r.CreateSession(other, refreshToken("other-1", "other-jti-1", now), noEmail); err != nil {
+		t.Fatal(err)
+	}
+
+	used, err := r.RotateRefreshToken("hash-1", refreshToken("hash-2", "jti-2", now), now)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken() = %v", err)
+	}
+	if used.FamilyID != session.ID || used.UserID != "user-1" {
+		t.Errorf("rotated token = %+v, want the token of the session", used)
+	}
+	if _, err := r.RotateRefreshToken("hash-2", refreshToken("hash-3", "jti-3", now), now); err != nil {
+		t.Fatalf("RotateRefreshToken() of the rotated token = %v", err)
+	}
+
+	// the first token is presented again, by the thief or by its owner
+	_, err = r.RotateRefreshToken("hash-1", refreshToken("hash-4", "jti-4", now), now)
+	if !errors.Is(err, ErrTokenReused) {
+		t.Fatalf("RotateRefreshToken() of a used token = %v, want ErrTokenReused", err)
+	}
+	_, err = r.RotateRefreshToken("hash-3", refreshToken("hash-5", "jti-5", now), now)
+	if !errors.Is(err, ErrTokenReused) {
+		t.Errorf("RotateRefreshToken() of the latest token of a revoked family = %v, want ErrTokenReused", err)
+	}
+
+	var family []model.RefreshToken
+	if err := db.Where("family_id = ?", session.ID).Find(&family).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(family) != 3 {
+		t.Errorf("family holds %d tokens, want 3, the reuses must not issue tokens", len(family))
+	}
+	for _, token := range family {
+		if token.RevokedAt == nil {
+			t.Errorf("token %s of the family is not revoked", token.TokenHash)
+		}
+	}
+	revoked, err := r.ListRevokedTokens(now.Add(-time.Minute), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	denied := map[string]bool{}
+	for _, token := range revoked {
+		denied[token.JTI] = true
+	}
+	for _, jti := range []string{"jti-1", "jti-2", "jti-3"} {
+		if !denied[jti] {
+			t.Errorf("access token %s of the family is not denied", jti)
+		}
+	}
+	if denied["other-jti-1"] {
+		t.Error("the access token of another session is denied")
+	}
+	sessions, err := r.ListSessions("user-1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != other.ID {
+		t.Errorf("active sessions = %v, want only the other session", sessions)
+	}
+
+	if _, err := r.RotateRefreshToken("other-1", refreshToken("other-2", "other-jti-2", now), now); err != nil {
+		t.Errorf("RotateRefreshToken() of another family = %v", err)
+	}
+	if _, err := r.RotateRefreshToken("other-2", refreshToken("other-3", "other-jti-3", now), now.Add(48*time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("RotateRefreshToken() of an expired token = %v, want ErrTokenExpired", err)
+	}
+	if _, err := r.RotateRefreshToken("unknown", refreshToken("hash-6", "jti-6", now), now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RotateRefreshToken() of an unknown token = %v, want ErrNotFound", err)
+	}
+}
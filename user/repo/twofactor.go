@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindTOTPFactor returns the authenticator app of the user, confirmed or not
+func (r *UserRepo) FindTOTPFactor(userID string) (*model.TOTPFactor, error) {
+	factor := &model.TOTPFactor{}
+	err := r.db.Where("user_id = ?", userID).First(factor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, translate(err)
+	}
+	return factor, nil
+}
+
+// SavePendingTOTP stores the authenticator app being enrolled, replacing an
+// unconfirmed one
+func (r *UserRepo) SavePendingTOTP(factor *model.TOTPFactor) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		current := &model.TOTPFactor{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", factor.UserID).First(current).Error
+		if err == nil && current.Confirmed() {
+			return ErrTwoFactorEnabled
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := tx.Where("user_id = ?", factor.UserID).Delete(&model.TOTPFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(factor).Error
+	}))
+}
+
+// ConfirmTOTP enables the pending authenticator app of the user, accepting
+// the time step of its first code, and replaces the recovery codes
+func (r *UserRepo) ConfirmTOTP(userID string, step int64, codes []*model.RecoveryCode, now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.TOTPFactor{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{"confirmed_at": now, "last_step": step})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTwoFactorEnabled
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(codes).Error
+	}))
+}
+
+// UseTOTPStep records the time step of an accepted code, the step must be
+// after the last accepted one so that a code cannot be used twice
+func (r *UserRepo) UseTOTPStep(userID string, step int64) error {
+	res := r.db.Model(&model.TOTPFactor{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_step < ?", userID, step).
+		Update("last_step", step)
+	if res.Error != nil {
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// UseRecoveryCode consumes the recovery code of the user with the hash
+func (r *UserRepo) UseRecoveryCode(userID string, hash string, now time.Time) error {
+	res := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	if res.Error != nil {
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// DeleteTwoFactor removes the authenticator app and the recovery codes of the user
+func (r *UserRepo) DeleteTwoFactor(userID string) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TOTPFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+	}))
+}
+
+// CreateLoginChallenge stores a login challenge
+func (r *UserRepo) CreateLoginChallenge(challenge *model.LoginChallenge) error {
+	return translate(r.db.Create(challenge).Error)
+}
+
+// AttemptLoginChallenge counts an attempt at the pending login challenge
+// with the hash and returns the challenge, provided it was attempted less
+// than maxAttempts times. The attempt is counted before the code is checked
+// so that concurrent attempts cannot go past the limit.
+func (r *UserRepo) AttemptLoginChallenge(hash string, now time.Time, maxAttempts int) (*model.LoginChallenge, error) {
+	challenge := &model.LoginChallenge{}
+	res := r.db.Model(challenge).Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?", hash, now, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if res.Error != nil {
+		return nil, translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrInvalidChallenge
+	}
+	return challenge, nil
+}
+
+// CompleteLoginChallenge consumes the challenge, it fails when the challenge
+// was completed concurrently or expired
+func (r *UserRepo) CompleteLoginChallenge(hash string, now time.Time) error {
+	res := r.db.Model(&model.LoginChallenge{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidChallenge
+	}
+	return nil
+}
+
+// DeleteExpiredChallenges removes the login challenges that expired
+func (r *UserRepo) DeleteExpiredChallenges(now time.Time) error {
+	return translate(r.db.Where("expires_at < ?", now).Delete(&model.LoginChallenge{}).Error)
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common"
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+)
+
+// testDB connects to the postgres database of USER_SVC_TEST_DSN, migrates
+// the models and empties their tables, the test is skipped without it
+func testDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("USER_SVC_TEST_DSN")
+	if dsn == "" {
+		t.Skip("USER_SVC_TEST_DSN is not set")
+	}
+	db, err := common.GetDBConnection(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range models {
+		if err := db.Unscoped().Where("1 = 1").Delete(m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestUseTOTPStepRejectsReplays(t *testing.T) {
+	r := NewUserRepo(testDB(t, &model.TOTPFactor{}, &model.RecoveryCode{}))
+	if err := r.SavePendingTOTP(&model.TOTPFactor{UserID: "user-1", Secret: "sealed"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UseTOTPStep("user-1", 100); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("UseTOTPStep() before the confirmation = %v, want ErrInvalidCode", err)
+	}
+	if err := r.ConfirmTOTP("user-1", 100, []*model.RecoveryCode{{UserID: "user-1", CodeHash: "hash"}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name string
+		step int64
+		want error
+	}{
+		{"step of the confirmation code", 100, ErrInvalidCode},
+		{"earlier step", 99, ErrInvalidCode},
+		{"next step", 101, nil},
+		{"replayed step", 101, ErrInvalidCode},
+		{"step within the drift", 103, nil},
+		{"skipped step", 102, ErrInvalidCode},
+	}
+	for _, tt := range steps {
+		if err := r.UseTOTPStep("user-1", tt.step); !errors.Is(err, tt.want) {
+			t.Errorf("UseTOTPStep() of the %s = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if err := r.UseTOTPStep("user-2", 104); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("UseTOTPStep() without a factor = %v, want ErrInvalidCode", err)
+	}
+}
@@ -1,11 +1,15 @@
 package repo
 
 import (
+	"errors"
 	"log"
 	"os"
+	"time"
 
+	"github.com/iamvasanth07/showcase/common/auth"
 	"github.com/iamvasanth07/showcase/user/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepo struct {
@@ -21,14 +25,15 @@ func NewUserRepo(db *gorm.DB) *UserRepo {
 }
 
 func (r *UserRepo) Create(user *model.User) error {
-	return r.db.Create(user).Error
+	return translate(r.db.Create(user).Error)
 }
 
+// FindByEmail returns the user with the email address, regardless of its case
 func (r *UserRepo) FindByEmail(email string) (*model.User, error) {
 	user := &model.User{}
-	err := r.db.Where("email = ?", email).First(user).Error
+	err := r.db.Where("lower(email) = lower(?)", email).First(user).Error
 	if err != nil {
-		return nil, err
+		return nil, translate(err)
 	}
 	return user, nil
 }
@@ -37,7 +42,7 @@ func (r *UserRepo) FindByPhone(phone string) (*model.User, error) {
 	user := &model.User{}
 	err := r.db.Where("phone = ?", phone).First(user).Error
 	if err != nil {
-		return nil, err
+		return nil, translate(err)
 	}
 	return user, nil
 }
@@ -46,26 +51,85 @@ func (r *UserRepo) FindByID(id string) (*model.User, error) {
 	user := &model.User{}
 	err := r.db.Where("uuid = ?", id).First(user).Error
 	if err != nil {
-		return nil, err
+		return nil, translate(err)
 	}
 	return user, nil
 }
 
 func (r *UserRepo) Update(user *model.User) error {
-	return r.db.Save(user).Error
+	return translate(r.db.Save(user).Error)
 }
 
+// Delete deletes the user along with its roles, failing with ErrLastAdmin
+// for the last admin
 func (r *UserRepo) Delete(id string) error {
-	user := &model.User{}
-	user.UUID = id
-	return r.db.Delete(user).Error
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		// the admin role row serializes the deletions with the revocations
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Role{}, "name = ?", auth.RoleAdmin).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var held int64
+		err = tx.Model(&model.UserRole{}).Where("user_id = ? AND role_name = ?", id, auth.RoleAdmin).Count(&held).Error
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			if err := requireOtherAdmin(tx, id); err != nil {
+				return err
+			}
+		}
+		res := tx.Where("uuid = ?", id).Delete(&model.User{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Where("user_id = ?", id).Delete(&model.UserRole{}).Error
+	}))
 }
 
 func (r *UserRepo) FindAll(page int32, limit int32) ([]*model.User, error) {
 	var users []*model.User
-	err := r.db.Offset(int(page)).Limit(int(limit)).Find(&users).Error
+	err := r.db.Order("created_at, uuid").Offset(int(page)).Limit(int(limit)).Find(&users).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return users, nil
+}
+
+// Count returns the number of users
+func (r *UserRepo) Count() (int64, error) {
+	var total int64
+	if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+		return 0, translate(err)
+	}
+	return total, nil
+}
+
+// Cursor is the position of the last user of a page, the next page starts after it
+type Cursor struct {
+	Key string
+	ID  string
+}
+
+// CursorOf returns the cursor of a user, users are listed by creation date
+func CursorOf(user *model.User) *Cursor {
+	return &Cursor{Key: user.CreatedAt.UTC().Format(time.RFC3339Nano), ID: user.UUID}
+}
+
+// FindAfter returns the users created after the cursor
+func (r *UserRepo) FindAfter(after *Cursor, limit int32) ([]*model.User, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, after.Key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var users []*model.User
+	err = r.db.Where("(created_at, uuid) > (?, ?)", createdAt, after.ID).
+		Order("created_at, uuid").Limit(int(limit)).Find(&users).Error
 	if err != nil {
-		return nil, err
+		return nil, translate(err)
 	}
 	return users, nil
 }
@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailLimit caps the emails of a kind a user can ask for
+type EmailLimit struct {
+	// Interval is the minimum time between two emails
+	Interval time.Duration
+	// Max is the number of emails allowed within Window
+	Max    int
+	Window time.Duration
+}
+
+// CreateWithEmail creates the user along with the email composed for it,
+// once the user has its id
+func (r *UserRepo) CreateWithEmail(user *model.User, compose func(*model.User) (*model.OutboxEmail, error)) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		email, err := compose(user)
+		if err != nil {
+			return err
+		}
+		return tx.Create(email).Error
+	}))
+}
+
+// UpdateWithEmail saves the user along with an email announcing the change
+func (r *UserRepo) UpdateWithEmail(user *model.User, email *model.OutboxEmail) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(email).Error
+	}))
+}
+
+// QueueEmail adds an email asked for by its user to the outbox, unless the
+// user went over the limit of its kind
+func (r *UserRepo) QueueEmail(email *model.OutboxEmail, limit EmailLimit, now time.Time) error {
+	return translate(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmailLimit(tx, email, limit, now); err != nil {
+			return err
+		}
+		return tx.Create(email).Error
+	}))
+}
+
+// checkEmailLimit fails with ErrRateLimited if the user of the email went
+// over the limit of its kind. It locks the user row, which serializes the
+// concurrent requests of the user until the end of the transaction.
+func checkEmailLimit(tx *gorm.DB, email *model.OutboxEmail, limit EmailLimit, now time.Time) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", email.UserID).First(&model.User{}).Error
+	if err != nil {
+		return err
+	}
+	var sent []time.Time
+	err = tx.Model(&model.OutboxEmail{}).
+		Where("user_id = ? AND kind = ? AND created_at > ?", email.UserID, email.Kind, now.Add(-limit.Window)).
+		Order("created_at DESC").Pluck("created_at", &sent).Error
+	if err != nil {
+		return err
+	}
+	if len(sent) >= limit.Max || (len(sent) > 0 && now.Sub(sent[0]) < limit.Interval) {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// VerifyEmail marks the email address of the user verified, provided it is
+// still the given one, and returns the user
+func (r *UserRepo) VerifyEmail(userID string, email string, now time.Time) (*model.User, error) {
+	user := &model.User{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", userID).First(user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.Email != email) {
+			return ErrInvalidVerificationToken
+		}
+		if err != nil || user.EmailVerified() {
+			return err
+		}
+		user.EmailVerifiedAt = &now
+		return tx.Model(user).Update("email_verified_at", now).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return user, nil
+}
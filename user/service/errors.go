@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/iamvasanth07/showcase/common/pagetoken"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus converts repo errors into grpc status errors, hiding anything unexpected
+func (s *UserServer) toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "user not found")
+	}
+	if errors.Is(err, repo.ErrRoleNotFound) {
+		return status.Error(codes.NotFound, repo.ErrRoleNotFound.Error())
+	}
+	if errors.Is(err, repo.ErrLastAdmin) {
+		return status.Error(codes.FailedPrecondition, repo.ErrLastAdmin.Error())
+	}
+	if errors.Is(err, repo.ErrTokenReused) || errors.Is(err, repo.ErrTokenExpired) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if errors.Is(err, repo.ErrTwoFactorNotEnabled) || errors.Is(err, repo.ErrTwoFactorEnabled) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidCode) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidChallenge) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if errors.Is(err, repo.ErrAccessTokenNotFound) || errors.Is(err, repo.ErrSessionNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidAccessToken) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidLoginState) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, repo.ErrRateLimited) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidResetToken) || errors.Is(err, repo.ErrInvalidVerificationToken) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, repo.ErrInvalidCursor) {
+		return status.Error(codes.InvalidArgument, pagetoken.ErrInvalid.Error())
+	}
+	var dup *repo.DuplicateError
+	if errors.As(err, &dup) {
+		st := status.New(codes.AlreadyExists, dup.Error())
+		detailed, detailErr := st.WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: dup.Field, Description: dup.Error()},
+			},
+		})
+		if detailErr != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+	s.log.Printf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	pb "github.com/iamvasanth07/showcase/common/protos/user"
 	"github.com/iamvasanth07/showcase/user/model"
 )
@@ -31,6 +33,7 @@ func UserToProto(user *model.User) *pb.User {
 	if user.Phone != "" {
 		userProto.Phone = user.Phone
 	}
+	userProto.EmailVerified = user.EmailVerified()
 	return userProto
 }
 
@@ -62,3 +65,47 @@ func ProtoToUser(user *pb.User) *model.User {
 	}
 	return userModel
 }
+
+// role models to role protos
+func RolesToProto(roles []model.Role) []*pb.Role {
+	rolesProto := make([]*pb.Role, 0, len(roles))
+	for _, role := range roles {
+		rolesProto = append(rolesProto, &pb.Role{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	return rolesProto
+}
+
+// personal access token model to personal access token proto
+func PersonalAccessTokenToProto(token *model.PersonalAccessToken) *pb.PersonalAccessToken {
+	tokenProto := &pb.PersonalAccessToken{
+		Id:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt.UTC().Format(time.RFC3339),
+		LastUsedIp: token.LastUsedIP,
+		CreatedAt:  token.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if token.LastUsedAt != nil {
+		tokenProto.LastUsedAt = token.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+	return tokenProto
+}
+
+// session model to session proto
+func SessionToProto(session *model.Session, current bool) *pb.Session {
+	return &pb.Session{
+		Id:         session.ID,
+		Device:     session.Device,
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		LastSeenIp: session.LastSeenIP,
+		CreatedAt:  session.CreatedAt.UTC().Format(time.RFC3339),
+		LastSeenAt: session.LastSeenAt.UTC().Format(time.RFC3339),
+		Current:    current,
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/utils"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// accountPolicy throttles the failed logins of an account
+var accountPolicy = repo.ThrottlePolicy{
+	MaxFailures: 5,
+	Window:      24 * time.Hour,
+	Base:        time.Minute,
+	Max:         24 * time.Hour,
+}
+
+// ipPolicy throttles the failed logins of a client address, more lenient
+// than accountPolicy as clients may share an address
+var ipPolicy = repo.ThrottlePolicy{
+	MaxFailures: 20,
+	Window:      24 * time.Hour,
+	Base:        time.Minute,
+	Max:         24 * time.Hour,
+}
+
+// dummyPasswordHash is compared with the passwords of the unknown emails so
+// that they take as long to reject as the wrong passwords
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("showcase-dummy-password"), bcrypt.DefaultCost)
+
+// throttle is a login throttle key along with its policy
+type throttle struct {
+	key    string
+	policy repo.ThrottlePolicy
+}
+
+// UnlockUser lifts the lockout of the account of a user
+func (s *UserServer) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	identity, err := s.authz.Require(ctx, auth.PermUserUnlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateUnlockUser(req); err != nil {
+		return nil, err
+	}
+	user, err := s.db.FindByID(req.UserId)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	err = s.db.Unlock(accountKey(user.Email), &model.AuditEvent{
+		UserID:  user.UUID,
+		ActorID: identity.UserID,
+		IP:      auth.ClientIPFromIncomingContext(ctx),
+	})
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("user %s unlocked by %s", user.UUID, identity.UserID)
+	return &pb.UnlockUserResponse{User: UserToProto(user)}, nil
+}
+
+// authenticatePassword returns the user with the email if the password
+// matches and neither the account nor the client address is locked. The
+// unknown emails are throttled and rejected like the wrong passwords so that
+// the responses do not tell whether an account exists.
+func (s *UserServer) authenticatePassword(ctx context.Context, email string, password string) (*model.User, error) {
+	now := time.Now()
+	ip := auth.ClientIPFromIncomingContext(ctx)
+	throttles := []throttle{{key: accountKey(email), policy: accountPolicy}}
+	if ip != "" {
+		throttles = append(throttles, throttle{key: "ip:" + ip, policy: ipPolicy})
+	}
+	keys := make([]string, len(throttles))
+	for i, t := range throttles {
+		keys[i] = t.key
+	}
+	until, err := s.db.LockedUntil(keys, now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if !until.IsZero() {
+		return nil, lockedError("too many failed logins, try again later", until.Sub(now))
+	}
+
+	user, err := s.db.FindByEmail(email)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return nil, s.toStatus(err)
+	}
+	// the users signed up with an external provider have no password
+	hasPassword := user != nil && user.Password != ""
+	hash := dummyPasswordHash
+	if hasPassword {
+		hash = []byte(user.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || !hasPassword {
+		var userID string
+		if user != nil {
+			userID = user.UUID
+		}
+		for _, t := range throttles {
+			event := &model.AuditEvent{UserID: userID, IP: ip}
+			recorded, err := s.db.RecordLoginFailure(t.key, t.policy, event, now)
+			if err != nil {
+				return nil, s.toStatus(err)
+			}
+			if recorded.LockedUntil != nil && recorded.LockedUntil.After(now) {
+				s.log.Printf("%s locked until %s after %d failed logins", t.key, recorded.LockedUntil.Format(time.RFC3339), recorded.Failures)
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "Invalid credentials")
+	}
+	if err := s.db.ResetLoginFailures(accountKey(email)); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return user, nil
+}
+
+// accountKey returns the throttle key of the account with the email
+func accountKey(email string) string {
+	return "account:" + email
+}
+
+// lockedError returns the status of the attempts rejected by a lock, telling
+// the client when to retry
+func lockedError(message string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, message)
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter.Round(time.Second))})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/config"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/oidc"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// oidcLoginExpiry is the time the user has to come back from the provider
+const oidcLoginExpiry = 10 * time.Minute
+
+// maxUsernameBase is the length of the usernames derived from the
+// provider accounts, before their random suffix
+const maxUsernameBase = 40
+
+var usernameStripRegex = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// identityStore is the part of the repository used by the logins with the
+// providers
+type identityStore interface {
+	CreateOIDCLogin(login *model.OIDCLogin) error
+	ConsumeOIDCLogin(hash string, provider string, now time.Time) (*model.OIDCLogin, error)
+	FindExternalIdentity(provider string, subject string) (*model.ExternalIdentity, error)
+	LinkExternalIdentity(identity *model.ExternalIdentity) error
+	CreateExternalUser(user *model.User, identity *model.ExternalIdentity) error
+	TouchExternalIdentity(identity *model.ExternalIdentity, email string, now time.Time) error
+	DeleteExternalIdentity(identity *model.ExternalIdentity) error
+	FindByID(id string) (*model.User, error)
+	FindByEmail(email string) (*model.User, error)
+}
+
+// StartOIDCLogin returns the authorization url of a provider along with the
+// state the callback has to carry
+func (s *UserServer) StartOIDCLogin(ctx context.Context, req *pb.StartOIDCLoginRequest) (*pb.StartOIDCLoginResponse, error) {
+	if err := utils.ValidateStartOIDCLogin(req); err != nil {
+		return nil, err
+	}
+	provider, err := s.oidcProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	login := &model.OIDCLogin{Provider: provider.Name(), ExpiresAt: time.Now().Add(oidcLoginExpiry)}
+	var state string
+	for _, value := range []*string{&state, &login.Nonce, &login.CodeVerifier} {
+		if *value, err = oidc.NewRandom(); err != nil {
+			return nil, s.toStatus(err)
+		}
+	}
+	login.StateHash = hashToken(state)
+	authURL, err := provider.AuthCodeURL(ctx, state, login.Nonce, login.CodeVerifier, req.LoginHint)
+	if err != nil {
+		return nil, s.oidcStatus(provider, err)
+	}
+	if err := s.identities.CreateOIDCLogin(login); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.StartOIDCLoginResponse{AuthorizationUrl: authURL, State: state}, nil
+}
+
+// CompleteOIDCLogin exchanges the code of a provider callback for the tokens
+// of the user linked to the provider account. The account is linked on first
+// login to the user with the same verified email address, or to a new user.
+func (s *UserServer) CompleteOIDCLogin(ctx context.Context, req *pb.CompleteOIDCLoginRequest) (*pb.LoginResponse, error) {
+	if err := utils.ValidateCompleteOIDCLogin(req); err != nil {
+		return nil, err
+	}
+	provider, err := s.oidcProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	login, err := s.identities.ConsumeOIDCLogin(hashToken(req.State), provider.Name(), now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	idToken, err := provider.Exchange(ctx, req.Code, login.CodeVerifier)
+	if err != nil {
+		return nil, s.oidcStatus(provider, err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, login.Nonce)
+	if err != nil {
+		return nil, s.oidcStatus(provider, err)
+	}
+	user, err := s.externalUser(provider.Name(), claims, now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.completeLogin(ctx, user)
+}
+
+// externalUser returns the user linked to the provider account of the claims,
+// linking or creating it on first login
+func (s *UserServer) externalUser(provider string, claims *oidc.Claims, now time.Time) (*model.User, error) {
+	email := strings.ToLower(claims.Email)
+	identity, err := s.identities.FindExternalIdentity(provider, claims.Subject)
+	switch {
+	case err == nil:
+		user, err := s.identities.FindByID(identity.UserID)
+		if err == nil {
+			return user, s.identities.TouchExternalIdentity(identity, email, now)
+		}
+		if !errors.Is(err, repo.ErrNotFound) {
+			return nil, err
+		}
+		// the user was deleted, the account is linked again below
+		if err := s.identities.DeleteExternalIdentity(identity); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, repo.ErrIdentityNotLinked):
+		return nil, err
+	}
+
+	// an unverified address could belong to anyone
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, status.Error(codes.FailedPrecondition, "the provider did not verify the email address of the account")
+	}
+	identity = &model.ExternalIdentity{
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: now,
+	}
+	user, err := s.identities.FindByEmail(email)
+	switch {
+	case err == nil:
+		// an unverified local account may have been registered by someone
+		// else waiting for the owner of the address to sign in
+		if !user.EmailVerified() {
+			return nil, status.Error(codes.FailedPrecondition, "an account with this email address is not verified, verify it before signing in with "+provider)
+		}
+		identity.UserID = user.UUID
+		if err := s.identities.LinkExternalIdentity(identity); err != nil {
+			return nil, err
+		}
+		s.log.Printf("%s account %s linked to user %s", provider, claims.Subject, user.UUID)
+		return user, nil
+	case errors.Is(err, repo.ErrNotFound):
+		user, err := newExternalUser(claims, email, now)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.identities.CreateExternalUser(user, identity); err != nil {
+			return nil, err
+		}
+		s.log.Printf("user %s signed up with %s", user.UUID, provider)
+		s.bootstrapAdmin(user)
+		return user, nil
+	default:
+		return nil, err
+	}
+}
+
+// newExternalUser returns the user signing up with a provider account, the
+// provider verified the email address and no password is set
+func newExternalUser(claims *oidc.Claims, email string, now time.Time) (*model.User, error) {
+	local := strings.SplitN(email, "@", 2)[0]
+	base := claims.PreferredUsername
+	if base == "" {
+		base = local
+	}
+	base = usernameStripRegex.ReplaceAllString(strings.ToLower(base), "")
+	if len(base) > maxUsernameBase {
+		base = base[:maxUsernameBase]
+	}
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	first, last := claims.GivenName, claims.FamilyName
+	if first == "" && last == "" {
+		names := strings.Fields(claims.Name)
+		if len(names) > 0 {
+			first, last = names[0], strings.Join(names[1:], " ")
+		}
+	}
+	if first == "" {
+		first = local
+	}
+	return &model.User{
+		FirstName:       first,
+		LastName:        last,
+		Username:        strings.TrimLeft(base+"-"+hex.EncodeToString(suffix), "-"),
+		Email:           email,
+		EmailVerifiedAt: &now,
+	}, nil
+}
+
+// completeLogin returns the tokens of the user, or the challenge of the
+// second step when the user enabled two-factor authentication
+func (s *UserServer) completeLogin(ctx context.Context, user *model.User) (*pb.LoginResponse, error) {
+	challenge, err := s.loginChallenge(user)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if challenge != "" {
+		return &pb.LoginResponse{ChallengeToken: challenge}, nil
+	}
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.LoginResponse{
+		Token:        tokens.access,
+		RefreshToken: tokens.refresh,
+		ExpiresIn:    tokens.expiresIn,
+	}, nil
+}
+
+// oidcProvider returns the configured provider with the name
+func (s *UserServer) oidcProvider(name string) (*oidc.Provider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown identity provider %q", name)
+	}
+	return provider, nil
+}
+
+// oidcStatus logs the failure of a provider and converts it, the details
+// stay in the log
+func (s *UserServer) oidcStatus(provider *oidc.Provider, err error) error {
+	s.log.Printf("login with %s failed: %v", provider.Name(), err)
+	switch {
+	case errors.Is(err, oidc.ErrUnavailable):
+		return status.Error(codes.Unavailable, oidc.ErrUnavailable.Error())
+	case errors.Is(err, oidc.ErrExchange):
+		return status.Error(codes.Unauthenticated, oidc.ErrExchange.Error())
+	case errors.Is(err, oidc.ErrInvalidIDToken):
+		return status.Error(codes.Unauthenticated, oidc.ErrInvalidIDToken.Error())
+	}
+	return s.toStatus(err)
+}
+
+// newOIDCProviders returns the configured providers by name
+func newOIDCProviders(settings *config.Settings) map[string]*oidc.Provider {
+	providers := make(map[string]*oidc.Provider)
+	if settings.OIDC == nil {
+		return providers
+	}
+	callback := strings.TrimSuffix(settings.OIDC.CallbackURL, "/")
+	for _, p := range settings.OIDC.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  callback + "/" + p.Name + "/callback",
+			Scopes:       p.Scopes,
+		}, nil)
+	}
+	return providers
+}
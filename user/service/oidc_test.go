@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/config"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/oidc"
+	"github.com/iamvasanth07/showcase/user/oidc/oidctest"
+	"github.com/iamvasanth07/showcase/user/repo"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memIdentities is an in-memory identityStore following the behavior of the
+// repository
+type memIdentities struct {
+	mu         sync.Mutex
+	logins     map[string]*model.OIDCLogin
+	identities map[string]*model.ExternalIdentity
+	users      map[string]*model.User
+}
+
+func newMemIdentities() *memIdentities {
+	return &memIdentities{
+		logins:     map[string]*model.OIDCLogin{},
+		identities: map[string]*model.ExternalIdentity{},
+		users:      map[string]*model.User{},
+	}
+}
+
+func (m *memIdentities) CreateOIDCLogin(login *model.OIDCLogin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logins[login.StateHash] = login
+	return nil
+}
+
+func (m *memIdentities) ConsumeOIDCLogin(hash string, provider string, now time.Time) (*model.OIDCLogin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	login, ok := m.logins[hash]
+	delete(m.logins, hash)
+	if !ok || login.Provider != provider || !login.ExpiresAt.After(now) {
+		return nil, repo.ErrInvalidLoginState
+	}
+	return login, nil
+}
+
+func (m *memIdentities) FindExternalIdentity(provider string, subject string) (*model.ExternalIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	identity, ok := m.identities[provider+"/"+subject]
+	if !ok {
+		return nil, repo.ErrIdentityNotLinked
+	}
+	return identity, nil
+}
+
+func (m *memIdentities) LinkExternalIdentity(identity *model.ExternalIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.identities[identity.Provider+"/"+identity.Subject] = identity
+	return nil
+}
+
+func (m *memIdentities) CreateExternalUser(user *model.User, identity *model.ExternalIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user.UUID = uuid.NewV4().String()
+	m.users[user.UUID] = user
+	identity.UserID = user.UUID
+	m.identities[identity.Provider+"/"+identity.Subject] = identity
+	return nil
+}
+
+func (m *memIdentities) TouchExternalIdentity(identity *model.ExternalIdentity, email string, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	identity.Email = email
+	identity.LastLoginAt = now
+	return nil
+}
+
+func (m *memIdentities) DeleteExternalIdentity(identity *model.ExternalIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.identities, identity.Provider+"/"+identity.Subject)
+	return nil
+}
+
+func (m *memIdentities) FindByID(id string) (*model.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return user, nil
+}
+
+// FindByEmail matches the addresses regardless of their case, like the
+// repository
+func (m *memIdentities) FindByEmail(email string) (*model.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (m *memIdentities) addUser(email string, verified bool) *model.User {
+	user := &model.User{UUID: uuid.NewV4().String(), Email: email, Username: "local", Password: "hash"}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	m.users[user.UUID] = user
+	return user
+}
+
+func newOIDCTestServer(t *testing.T) (*UserServer, *memIdentities, *oidctest.Provider) {
+	t.Helper()
+	server, mock, err := oidctest.NewServer("showcase", "showcase-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	providers := map[string]*oidc.Provider{}
+	for _, name := range []string{"mock", "other"} {
+		providers[name] = oidc.NewProvider(oidc.Config{
+			Name:         name,
+			Issuer:       server.URL,
+			ClientID:     "showcase",
+			ClientSecret: "showcase-secret",
+			RedirectURL:  "http://localhost:8080/api/v1/user/oidc/" + name + "/callback",
+		}, server.Client())
+	}
+	store := newMemIdentities()
+	s := &UserServer{
+		identities: store,
+		providers:  providers,
+		log:        log.New(io.Discard, "", 0),
+		settings:   config.GetSettings(),
+	}
+	return s, store, mock
+}
+
+// startLogin starts a login with the mock provider and returns the callback
+// request the provider sent the user back with
+func startLogin(t *testing.T, s *UserServer, email string) *pb.CompleteOIDCLoginRequest {
+	t.Helper()
+	res, err := s.StartOIDCLogin(context.Background(), &pb.StartOIDCLoginRequest{Provider: "mock", LoginHint: email})
+	if err != nil {
+		t.Fatalf("StartOIDCLogin() error = %v", err)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	authorization, err := client.Get(res.AuthorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorization.Body.Close()
+	location, err := url.Parse(authorization.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.CompleteOIDCLoginRequest{
+		Provider: "mock",
+		Code:     location.Query().Get("code"),
+		State:    location.Query().Get("state"),
+	}
+}
+
+func TestCompleteOIDCLoginRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		// tamper changes the callback or the stored login before completion
+		tamper func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider)
+		want   codes.Code
+	}{
+		{
+			name: "state mismatch",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				req.State += "x"
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "state of another provider",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				req.Provider = "other"
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "replayed state",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				store.ConsumeOIDCLogin(hashToken(req.State), req.Provider, time.Now())
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "expired state",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				store.logins[hashToken(req.State)].ExpiresAt = time.Now().Add(-time.Second)
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "pkce verifier mismatch",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				store.logins[hashToken(req.State)].CodeVerifier += "x"
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "nonce mismatch",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				store.logins[hashToken(req.State)].Nonce += "x"
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "unverified email",
+			tamper: func(req *pb.CompleteOIDCLoginRequest, store *memIdentities, mock *oidctest.Provider) {
+				mock.EmailVerified = false
+			},
+			want: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, mock := newOIDCTestServer(t)
+			req := startLogin(t, s, "alice@example.com")
+			tt.tamper(req, store, mock)
+			_, err := s.CompleteOIDCLogin(context.Background(), req)
+			if status.Code(err) != tt.want {
+				t.Errorf("CompleteOIDCLogin() error = %v, want %s", err, tt.want)
+			}
+			if len(store.users) != 0 || len(store.identities) != 0 {
+				t.Errorf("a rejected login created %d users and %d identities", len(store.users), len(store.identities))
+			}
+		})
+	}
+}
+
+func TestExternalUser(t *testing.T) {
+	claims := func(email string, verified bool) *oidc.Claims {
+		c := &oidc.Claims{Subject: "subject", Email: email, Name: "Alice Liddell"}
+		if verified {
+			c.EmailVerified = true
+		}
+		return c
+	}
+	t.Run("links a verified account regardless of the case of the address", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		existing := store.addUser("alice@example.com", true)
+		user, err := s.externalUser("mock", claims("Alice@Example.com", true), time.Now())
+		if err != nil {
+			t.Fatalf("externalUser() error = %v", err)
+		}
+		if user.UUID != existing.UUID || len(store.users) != 1 {
+			t.Errorf("got user %s among %d users, want the existing %s", user.UUID, len(store.users), existing.UUID)
+		}
+		identity, err := store.FindExternalIdentity("mock", "subject")
+		if err != nil || identity.UserID != existing.UUID {
+			t.Errorf("identity = %+v (%v), want linked to %s", identity, err, existing.UUID)
+		}
+	})
+	t.Run("refuses an unverified account with the address", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		store.addUser("alice@example.com", false)
+		_, err := s.externalUser("mock", claims("alice@example.com", true), time.Now())
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("externalUser() error = %v, want %s", err, codes.FailedPrecondition)
+		}
+		if len(store.identities) != 0 {
+			t.Error("the provider account was linked to the unverified account")
+		}
+	})
+	t.Run("refuses an address the provider did not verify", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		store.addUser("alice@example.com", true)
+		_, err := s.externalUser("mock", claims("alice@example.com", false), time.Now())
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("externalUser() error = %v, want %s", err, codes.FailedPrecondition)
+		}
+	})
+	t.Run("creates a verified user without password", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		user, err := s.externalUser("mock", claims("Alice@Example.com", true), time.Now())
+		if err != nil {
+			t.Fatalf("externalUser() error = %v", err)
+		}
+		if user.Email != "alice@example.com" || !user.EmailVerified() || user.Password != "" {
+			t.Errorf("user = %+v, want a verified alice@example.com without password", user)
+		}
+		if user.FirstName != "Alice" || user.LastName != "Liddell" || !strings.HasPrefix(user.Username, "alice-") {
+			t.Errorf("user named %s %s (%s), want Alice Liddell (alice-...)", user.FirstName, user.LastName, user.Username)
+		}
+		if _, err := store.FindExternalIdentity("mock", "subject"); err != nil {
+			t.Errorf("the provider account was not linked: %v", err)
+		}
+	})
+	t.Run("returns the linked user on later logins", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		first, err := s.externalUser("mock", claims("alice@example.com", true), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the address changed at the provider, the subject did not
+		again, err := s.externalUser("mock", claims("alice@wonderland.example", false), time.Now())
+		if err != nil {
+			t.Fatalf("externalUser() error = %v", err)
+		}
+		if again.UUID != first.UUID || len(store.users) != 1 {
+			t.Errorf("got user %s among %d users, want %s", again.UUID, len(store.users), first.UUID)
+		}
+		if identity, _ := store.FindExternalIdentity("mock", "subject"); identity.Email != "alice@wonderland.example" {
+			t.Errorf("identity email = %s, want the new address", identity.Email)
+		}
+	})
+	t.Run("links again after the user was deleted", func(t *testing.T) {
+		s, store, _ := newOIDCTestServer(t)
+		first, err := s.externalUser("mock", claims("alice@example.com", true), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		delete(store.users, first.UUID)
+		again, err := s.externalUser("mock", claims("alice@example.com", true), time.Now())
+		if err != nil {
+			t.Fatalf("externalUser() error = %v", err)
+		}
+		identity, _ := store.FindExternalIdentity("mock", "subject")
+		if again.UUID == first.UUID || identity.UserID != again.UUID {
+			t.Errorf("identity linked to %s, want the new user %s", identity.UserID, again.UUID)
+		}
+	})
+}
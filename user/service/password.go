@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/mailer"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/utils"
+)
+
+// defaultResetExpiry is used when USER_SVC_PASSWORD_RESET_EXPIRY is not set
+const defaultResetExpiry = 30 * time.Minute
+
+// outbox delivery settings
+const (
+	outboxInterval    = 10 * time.Second
+	outboxBatchSize   = 20
+	outboxMaxAttempts = 8
+	// outboxSendTimeout bounds the delivery of an email to the mail server
+	outboxSendTimeout = 30 * time.Second
+	// the claimed emails are left alone by the other instances for longer
+	// than the delivery of a batch
+	outboxClaimLease = outboxBatchSize * outboxSendTimeout * 2
+	// sent emails are kept a while to investigate delivery issues
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// resetLimit caps the reset emails a user can receive
+var resetLimit = repo.EmailLimit{
+	Interval: time.Minute,
+	Max:      5,
+	Window:   24 * time.Hour,
+}
+
+// resetQueueSize bounds the reset requests waiting for ProcessPasswordResets
+const resetQueueSize = 100
+
+// RequestPasswordReset emails a reset token to the user with the email. The
+// token is created after the response so that neither the response nor its
+// timing tell whether the email belongs to a user, nor whether the user went
+// over the limit of the reset emails. The requests arriving while the queue
+// is full are dropped.
+func (s *UserServer) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if err := utils.ValidateRequestPasswordReset(req); err != nil {
+		return nil, err
+	}
+	select {
+	case s.resetRequests <- req.Email:
+	default:
+		s.log.Printf("password reset queue is full, dropping a request")
+	}
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+// ProcessPasswordResets creates the password resets of the queued requests
+// one at a time until the context is cancelled
+func (s *UserServer) ProcessPasswordResets(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case address := <-s.resetRequests:
+			err := s.createPasswordReset(address)
+			if errors.Is(err, repo.ErrRateLimited) {
+				s.log.Printf("password reset limit reached, request ignored")
+			} else if err != nil {
+				s.log.Printf("failed to create a password reset: %v", err)
+			}
+		}
+	}
+}
+
+// createPasswordReset stores a reset token of the user with the email and
+// queues the email carrying it, unknown emails are ignored
+func (s *UserServer) createPasswordReset(address string) error {
+	user, err := s.db.FindByEmail(address)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	expiry := s.resetExpiry()
+	token := &model.PasswordResetToken{
+		UserID:    user.UUID,
+		TokenHash: hashToken(secret),
+		ExpiresAt: now.Add(expiry),
+	}
+	email := &model.OutboxEmail{
+		Recipient: user.Email,
+		UserID:    user.UUID,
+		Kind:      model.EmailPasswordReset,
+		Subject:   "Reset your Showcase password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Someone asked to reset the password of your Showcase account. Open the link below within %d minutes to choose a new password:\n\n"+
+			"%s\n\n"+
+			"If you did not ask for it, ignore this email, your password is unchanged.\n",
+			user.FirstName, int(expiry.Minutes()), s.resetLink(secret)),
+	}
+	return s.db.CreatePasswordReset(token, email, resetLimit, now)
+}
+
+// ResetPassword replaces the password of the user of a reset token and signs
+// the user out of every session, revoking the personal access tokens too
+func (s *UserServer) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if err := utils.ValidateResetPassword(req); err != nil {
+		return nil, err
+	}
+	passwordHash, err := model.HashPassword(req.Password)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	userID, err := s.db.ResetPassword(hashToken(req.Token), passwordHash, time.Now())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("password of user %s reset", userID)
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+// resetLink returns the link of the reset page carrying the token
+func (s *UserServer) resetLink(secret string) string {
+	link, err := url.Parse(s.settings.PasswordReset.URL)
+	if err != nil || s.settings.PasswordReset.URL == "" {
+		return "Reset token: " + secret
+	}
+	query := link.Query()
+	query.Set("token", secret)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// resetExpiry returns the lifetime of the reset tokens
+func (s *UserServer) resetExpiry() time.Duration {
+	if s.settings.PasswordReset.Expiry > 0 {
+		return time.Duration(s.settings.PasswordReset.Expiry) * time.Minute
+	}
+	return defaultResetExpiry
+}
+
+// DeliverEmails periodically sends the emails of the outbox
+func (s *UserServer) DeliverEmails(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.deliverEmails(ctx); err != nil {
+			s.log.Printf("failed to deliver the outbox: %v", err)
+		}
+	}
+}
+
+// deliverEmails sends a batch of the due emails of the outbox. The emails are
+// claimed first and sent outside of any transaction, each within a timeout.
+func (s *UserServer) deliverEmails(ctx context.Context) error {
+	emails, err := s.db.ClaimEmails(time.Now(), outboxBatchSize, outboxMaxAttempts, outboxClaimLease)
+	if err != nil {
+		return err
+	}
+	for i := range emails {
+		email := &emails[i]
+		sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+		err := s.mailer.Send(sendCtx, &mailer.Message{
+			From:    s.settings.Mail.From,
+			To:      email.Recipient,
+			Subject: email.Subject,
+			Body:    email.Body,
+		})
+		cancel()
+		if err != nil {
+			s.log.Printf("failed to send email %s: %v", email.ID, err)
+			err = s.db.MarkEmailFailed(email, err.Error(), time.Now().Add(outboxBackoff(email.Attempts)), outboxMaxAttempts)
+		} else {
+			err = s.db.MarkEmailSent(email, time.Now())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outboxBackoff doubles the delay between the attempts, from 30 seconds
+func outboxBackoff(attempt int) time.Duration {
+	return (30 * time.Second) << (attempt - 1)
+}
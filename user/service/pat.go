@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// personal access token settings
+const (
+	// tokenPrefixSize is the number of characters of the token after its
+	// prefix kept to recognize it in the listings
+	tokenPrefixSize = 4
+	// tokenUseGranularity is the precision of the last use of the tokens
+	tokenUseGranularity = time.Minute
+	// revoked and expired tokens stay listed a while
+	tokenRetention = 30 * 24 * time.Hour
+)
+
+// CreatePersonalAccessToken creates a personal access token of the caller,
+// the token is only returned by this call
+func (s *UserServer) CreatePersonalAccessToken(ctx context.Context, req *pb.CreatePersonalAccessTokenRequest) (*pb.CreatePersonalAccessTokenResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateCreatePersonalAccessToken(req); err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	value := auth.PersonalAccessTokenPrefix + secret
+	token := &model.PersonalAccessToken{
+		UserID:    identity.UserID,
+		Name:      req.Name,
+		Prefix:    value[:len(auth.PersonalAccessTokenPrefix)+tokenPrefixSize],
+		TokenHash: hashToken(value),
+		Scopes:    req.Scopes,
+		ExpiresAt: time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour),
+	}
+	if err := s.db.CreatePersonalAccessToken(token); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.CreatePersonalAccessTokenResponse{
+		Token:               value,
+		PersonalAccessToken: PersonalAccessTokenToProto(token),
+	}, nil
+}
+
+// ListPersonalAccessTokens returns the unrevoked personal access tokens of the caller
+func (s *UserServer) ListPersonalAccessTokens(ctx context.Context, req *pb.ListPersonalAccessTokensRequest) (*pb.ListPersonalAccessTokensResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.db.ListPersonalAccessTokens(identity.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListPersonalAccessTokensResponse{}
+	for i := range tokens {
+		res.PersonalAccessTokens = append(res.PersonalAccessTokens, PersonalAccessTokenToProto(&tokens[i]))
+	}
+	return res, nil
+}
+
+// RevokePersonalAccessToken revokes a personal access token of the caller
+func (s *UserServer) RevokePersonalAccessToken(ctx context.Context, req *pb.RevokePersonalAccessTokenRequest) (*pb.RevokePersonalAccessTokenResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateRevokePersonalAccessToken(req); err != nil {
+		return nil, err
+	}
+	if err := s.db.RevokePersonalAccessToken(req.Id, identity.UserID, time.Now()); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.RevokePersonalAccessTokenResponse{}, nil
+}
+
+// ValidatePersonalAccessToken returns the identity of a personal access
+// token and records its use
+func (s *UserServer) ValidatePersonalAccessToken(ctx context.Context, req *pb.ValidatePersonalAccessTokenRequest) (*pb.ValidatePersonalAccessTokenResponse, error) {
+	if !auth.IsPersonalAccessToken(req.Token) {
+		return nil, s.toStatus(repo.ErrInvalidAccessToken)
+	}
+	token, err := s.db.UsePersonalAccessToken(hashToken(req.Token), req.ClientIp, time.Now(), tokenUseGranularity)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	user, err := s.db.FindByID(token.UserID)
+	if err != nil {
+		return nil, s.toStatus(repo.ErrInvalidAccessToken)
+	}
+	return &pb.ValidatePersonalAccessTokenResponse{
+		UserId:        user.UUID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified(),
+		Scopes:        token.Scopes,
+	}, nil
+}
+
+// interactiveCaller returns the caller of a request authenticated with a
+// jwt, personal access tokens cannot manage the tokens
+func (s *UserServer) interactiveCaller(ctx context.Context) (*auth.Identity, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if identity.PersonalAccessToken() {
+		return nil, status.Error(codes.PermissionDenied, "personal access tokens cannot manage tokens")
+	}
+	return identity, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/utils"
+)
+
+// AssignRole grants a role to a user, the new permissions apply to the next token
+func (s *UserServer) AssignRole(ctx context.Context, req *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error) {
+	identity, err := s.authz.Require(ctx, auth.PermRoleManage)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateRoleChange(req.UserId, req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.db.AssignRole(req.UserId, req.Role); err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("role %s assigned to user %s by %s", req.Role, req.UserId, identity.UserID)
+	roles, err := s.db.FindUserRoles(req.UserId)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.AssignRoleResponse{UserId: req.UserId, Roles: RolesToProto(roles)}, nil
+}
+
+// RevokeRole takes a role back from a user
+func (s *UserServer) RevokeRole(ctx context.Context, req *pb.RevokeRoleRequest) (*pb.RevokeRoleResponse, error) {
+	identity, err := s.authz.Require(ctx, auth.PermRoleManage)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateRoleChange(req.UserId, req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.db.RevokeRole(req.UserId, req.Role); err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("role %s revoked from user %s by %s", req.Role, req.UserId, identity.UserID)
+	roles, err := s.db.FindUserRoles(req.UserId)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.RevokeRoleResponse{UserId: req.UserId, Roles: RolesToProto(roles)}, nil
+}
+
+// ListRoles returns the roles of a user, or every role
+func (s *UserServer) ListRoles(ctx context.Context, req *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	if _, err := s.authz.Require(ctx, auth.PermRoleManage); err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateListRoles(req); err != nil {
+		return nil, err
+	}
+	var roles []model.Role
+	var err error
+	if req.UserId != "" {
+		if _, err := s.db.FindByID(req.UserId); err != nil {
+			return nil, s.toStatus(err)
+		}
+		roles, err = s.db.FindUserRoles(req.UserId)
+	} else {
+		roles, err = s.db.ListRoles()
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.ListRolesResponse{Roles: RolesToProto(roles)}, nil
+}
+
+// bootstrapAdmin grants the admin role to the user configured with
+// USER_SVC_ADMIN_EMAIL while no user holds it. The address must be verified,
+// otherwise anyone signing up with it first would become admin.
+func (s *UserServer) bootstrapAdmin(user *model.User) {
+	email := s.settings.Bootstrap.AdminEmail
+	if email == "" || !strings.EqualFold(email, user.Email) {
+		return
+	}
+	if !user.EmailVerified() {
+		s.log.Printf("the admin role is granted to %s once the address is verified", user.Email)
+		return
+	}
+	admins, err := s.db.CountRoleMembers(auth.RoleAdmin)
+	if err != nil {
+		s.log.Printf("failed to count the admins: %v", err)
+		return
+	}
+	if admins > 0 {
+		return
+	}
+	if err := s.db.AssignRole(user.UUID, auth.RoleAdmin); err != nil {
+		s.log.Printf("failed to bootstrap the admin %s: %v", user.Email, err)
+		return
+	}
+	s.log.Printf("admin role granted to %s", user.Email)
+}
+
+// grants returns the names of the roles and the union of their permissions
+func grants(roles []model.Role) ([]string, []string) {
+	names := make([]string, 0, len(roles))
+	seen := make(map[string]bool)
+	var permissions []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, permission := range role.Permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return names, permissions
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/utils"
+)
+
+// session settings
+const (
+	// maxUserAgentSize truncates the user agents stored with the sessions
+	maxUserAgentSize = 512
+	// ended sessions are kept a while to recognize the devices they were
+	// signed in from
+	sessionRetention = 90 * 24 * time.Hour
+)
+
+// browserMarkers and systemMarkers map the markers of the user agents to the
+// browsers and operating systems shown to the users, the first match wins
+var (
+	browserMarkers = [][2]string{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"FxiOS/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"CriOS/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	systemMarkers = [][2]string{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iOS"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// ListSessions returns the active sessions of the caller
+func (s *UserServer) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.db.FindSessionID(identity.TokenID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	sessions, err := s.db.ListSessions(identity.UserID, time.Now())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListSessionsResponse{}
+	for i := range sessions {
+		res.Sessions = append(res.Sessions, SessionToProto(&sessions[i], sessions[i].ID == current))
+	}
+	return res, nil
+}
+
+// RevokeSession signs out a session of the caller, its access tokens are
+// denied at once
+func (s *UserServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateRevokeSession(req); err != nil {
+		return nil, err
+	}
+	if err := s.db.RevokeSession(req.Id, identity.UserID, time.Now()); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+// RevokeAllOtherSessions signs out every session of the caller but the one
+// of the request and revokes the personal access tokens of the caller
+func (s *UserServer) RevokeAllOtherSessions(ctx context.Context, req *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeAllOtherSessionsResponse, error) {
+	identity, err := s.interactiveCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.db.FindSessionID(identity.TokenID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	revoked, err := s.db.RevokeOtherSessions(identity.UserID, current, time.Now())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("user %s revoked %d other sessions", identity.UserID, revoked)
+	return &pb.RevokeAllOtherSessionsResponse{Revoked: int32(revoked)}, nil
+}
+
+// newSession returns the session of a login from the client of the request
+func newSession(ctx context.Context, userID string, now time.Time, expiresAt time.Time) *model.Session {
+	userAgent := auth.UserAgentFromIncomingContext(ctx)
+	if len(userAgent) > maxUserAgentSize {
+		userAgent = userAgent[:maxUserAgentSize]
+	}
+	ip := auth.ClientIPFromIncomingContext(ctx)
+	return &model.Session{
+		UserID:     userID,
+		UserAgent:  userAgent,
+		Device:     deviceName(userAgent),
+		DeviceHash: hashToken(userAgent),
+		IP:         ip,
+		LastSeenIP: ip,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+// newDeviceEmail returns the email announcing a login from a new device
+func (s *UserServer) newDeviceEmail(user *model.User, session *model.Session) *model.OutboxEmail {
+	ip := session.IP
+	if ip == "" {
+		ip = "unknown"
+	}
+	return &model.OutboxEmail{
+		Recipient: user.Email,
+		UserID:    user.UUID,
+		Kind:      model.EmailNewDevice,
+		Subject:   "New sign-in to your Showcase account",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Your Showcase account was just signed in from a new device:\n\n"+
+			"Device: %s\n"+
+			"Address: %s\n"+
+			"Time: %s\n\n"+
+			"If it was you, there is nothing to do. Otherwise reset your password and sign out the device from your sessions.\n",
+			user.FirstName, session.Device, ip, session.LastSeenAt.UTC().Format(time.RFC1123)),
+	}
+}
+
+// deviceName returns a readable form of a user agent, like "Firefox on
+// Linux", or the product of the agents that are not browsers
+func deviceName(userAgent string) string {
+	match := func(markers [][2]string) string {
+		for _, marker := range markers {
+			if strings.Contains(userAgent, marker[0]) {
+				return marker[1]
+			}
+		}
+		return ""
+	}
+	browser, system := match(browserMarkers), match(systemMarkers)
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	product := strings.FieldsFunc(userAgent, func(r rune) bool { return r == '/' || r == ' ' })
+	if len(product) == 0 {
+		return "Unknown device"
+	}
+	return product[0]
+}
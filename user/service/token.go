@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/jwks"
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/config"
+	"github.com/iamvasanth07/showcase/user/keys"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultAccessExpiry is used when USER_SVC_JWT_EXPIRY is not set
+const defaultAccessExpiry = 15 * time.Minute
+
+// defaultRefreshExpiry is used when USER_SVC_REFRESH_TOKEN_EXPIRY is not set
+const defaultRefreshExpiry = 30 * 24 * time.Hour
+
+// tokenSize is the number of random bytes of the refresh and reset tokens
+const tokenSize = 32
+
+// defaultKeyGrace is used when USER_SVC_JWT_KEY_GRACE is not set
+const defaultKeyGrace = 24 * time.Hour
+
+// keyRotationCheck is the interval between the checks of the signing key age
+const keyRotationCheck = time.Hour
+
+// tokens are an access token and the refresh token issued with it
+type tokens struct {
+	access    string
+	refresh   string
+	expiresIn int64
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Presenting a refresh token twice revokes every token of its family.
+func (s *UserServer) Refresh(ctx context.Context, req *pb.RefreshRequest) (*pb.RefreshResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.Unauthenticated, "refresh token is required")
+	}
+	now := time.Now()
+	secret, next, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	current, err := s.db.RotateRefreshToken(hashToken(req.RefreshToken), next, now)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+	if errors.Is(err, repo.ErrTokenReused) {
+		s.log.Printf("refresh token reuse detected, token family revoked")
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	user, err := s.db.FindByID(current.UserID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.TouchSession(current.FamilyID, auth.ClientIPFromIncomingContext(ctx), next.ExpiresAt, now); err != nil {
+		return nil, s.toStatus(err)
+	}
+	access, err := s.generateJWTToken(user, next.AccessTokenID, next.AccessTokenExpiresAt)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.RefreshResponse{
+		Token:        access,
+		RefreshToken: secret,
+		ExpiresIn:    int64(s.accessExpiry().Seconds()),
+	}, nil
+}
+
+// Logout revokes the access token of the caller and the family of the
+// refresh token, if any
+func (s *UserServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if identity.TokenID != "" {
+		// the access token expires at the latest after the access expiry
+		if err := s.db.RevokeAccessToken(identity.TokenID, now.Add(s.accessExpiry())); err != nil {
+			return nil, s.toStatus(err)
+		}
+	}
+	if req.RefreshToken != "" {
+		err := s.db.RevokeRefreshFamily(hashToken(req.RefreshToken), identity.UserID, now)
+		if err != nil && !errors.Is(err, repo.ErrNotFound) {
+			return nil, s.toStatus(err)
+		}
+	}
+	return &pb.LogoutResponse{}, nil
+}
+
+// ListRevokedTokens returns the unexpired access tokens revoked since the
+// time of the request
+func (s *UserServer) ListRevokedTokens(ctx context.Context, req *pb.ListRevokedTokensRequest) (*pb.ListRevokedTokensResponse, error) {
+	var since time.Time
+	if req.Since != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339Nano, req.Since)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "since must be an RFC 3339 time")
+		}
+	}
+	now := time.Now()
+	revoked, err := s.db.ListRevokedTokens(since, now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.ListRevokedTokensResponse{AsOf: now.UTC().Format(time.RFC3339Nano)}
+	for _, token := range revoked {
+		res.Tokens = append(res.Tokens, &pb.RevokedToken{
+			Id:        token.JTI,
+			ExpiresAt: token.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+// GetJWKS returns the public keys verifying the access tokens
+func (s *UserServer) GetJWKS(ctx context.Context, req *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error) {
+	set, err := s.keys.PublicKeys()
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	res := &pb.GetJWKSResponse{}
+	for _, key := range set.Keys {
+		res.Keys = append(res.Keys, &pb.JsonWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return res, nil
+}
+
+// initKeys loads the signing keys. Retired keys stay published at least as
+// long as the access tokens they signed are valid.
+func initKeys(settings *config.Settings, logger *log.Logger) (*keys.KeyRing, error) {
+	if settings.JWT.KeysDir == "" {
+		return nil, errors.New("USER_SVC_JWT_KEYS_DIR is required")
+	}
+	algorithm := settings.JWT.Algorithm
+	if algorithm == "" {
+		algorithm = jwks.AlgEdDSA
+	}
+	grace := defaultKeyGrace
+	if settings.JWT.Grace > 0 {
+		grace = time.Duration(settings.JWT.Grace) * time.Hour
+	}
+	expiry := time.Duration(settings.JWT.Expiry) * time.Minute
+	if expiry <= 0 {
+		expiry = defaultAccessExpiry
+	}
+	if grace < expiry {
+		grace = expiry
+	}
+	rotation := time.Duration(settings.JWT.Rotation) * time.Hour
+	return keys.NewKeyRing(settings.JWT.KeysDir, algorithm, rotation, grace, logger)
+}
+
+// issueTokens starts a new session and token family for a user who just
+// authenticated from the client of the request
+func (s *UserServer) issueTokens(ctx context.Context, user *model.User) (*tokens, error) {
+	now := time.Now()
+	secret, refresh, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+	session := newSession(ctx, user.UUID, now, refresh.ExpiresAt)
+	err = s.db.CreateSession(session, refresh, func(session *model.Session) *model.OutboxEmail {
+		return s.newDeviceEmail(user, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	access, err := s.generateJWTToken(user, refresh.AccessTokenID, refresh.AccessTokenExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens{
+		access:    access,
+		refresh:   secret,
+		expiresIn: int64(s.accessExpiry().Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns a random refresh token and its record, along with
+// the jti of the access token issued with it
+func (s *UserServer) newRefreshToken(now time.Time) (string, *model.RefreshToken, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, &model.RefreshToken{
+		TokenHash:            hashToken(secret),
+		AccessTokenID:        uuid.NewV4().String(),
+		AccessTokenExpiresAt: now.Add(s.accessExpiry()),
+		ExpiresAt:            now.Add(s.refreshExpiry()),
+	}, nil
+}
+
+// newSecret returns a random url-safe token
+func newSecret() (string, error) {
+	buf := make([]byte, tokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the hash under which a refresh or reset token is stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// accessExpiry returns the lifetime of the access tokens
+func (s *UserServer) accessExpiry() time.Duration {
+	if s.settings.JWT.Expiry > 0 {
+		return time.Duration(s.settings.JWT.Expiry) * time.Minute
+	}
+	return defaultAccessExpiry
+}
+
+// refreshExpiry returns the lifetime of the refresh tokens
+func (s *UserServer) refreshExpiry() time.Duration {
+	if s.settings.JWT.RefreshExpiry > 0 {
+		return time.Duration(s.settings.JWT.RefreshExpiry) * time.Hour
+	}
+	return defaultRefreshExpiry
+}
+
+// CleanupExpiredTokens periodically removes the expired refresh, reset and
+// revoked access tokens, login challenges, unfinished external logins, ended
+// sessions, stale login throttles and personal access tokens along with the
+// old sent emails
+func (s *UserServer) CleanupExpiredTokens(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		if err := s.db.DeleteExpiredTokens(now); err != nil {
+			s.log.Printf("failed to remove expired tokens: %v", err)
+		}
+		if err := s.db.DeleteExpiredResetTokens(now); err != nil {
+			s.log.Printf("failed to remove expired reset tokens: %v", err)
+		}
+		if err := s.db.DeleteExpiredChallenges(now); err != nil {
+			s.log.Printf("failed to remove expired login challenges: %v", err)
+		}
+		if err := s.db.DeleteExpiredOIDCLogins(now); err != nil {
+			s.log.Printf("failed to remove expired external logins: %v", err)
+		}
+		if err := s.db.DeleteEndedSessions(now.Add(-sessionRetention)); err != nil {
+			s.log.Printf("failed to remove ended sessions: %v", err)
+		}
+		if err := s.db.DeleteStaleThrottles(now.Add(-accountPolicy.Window), now); err != nil {
+			s.log.Printf("failed to remove stale login throttles: %v", err)
+		}
+		if err := s.db.DeleteStaleAccessTokens(now.Add(-tokenRetention)); err != nil {
+			s.log.Printf("failed to remove stale personal access tokens: %v", err)
+		}
+		if err := s.db.DeleteSentEmails(now.Add(-outboxRetention)); err != nil {
+			s.log.Printf("failed to remove sent emails: %v", err)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/totp"
+	"github.com/iamvasanth07/showcase/user/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// login challenge settings
+const (
+	challengeExpiry = 5 * time.Minute
+	// a challenge is dropped after this many codes, the user has to present
+	// the password again
+	challengeMaxAttempts = 5
+)
+
+// codePolicy throttles the wrong codes presented by a signed in user to
+// confirm or disable their authenticator app
+var codePolicy = repo.ThrottlePolicy{
+	MaxFailures: 5,
+	Window:      time.Hour,
+	Base:        time.Minute,
+	Max:         time.Hour,
+}
+
+// recovery code settings
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeSize is the number of random bytes of a recovery code
+	recoveryCodeSize = 10
+)
+
+// defaultTOTPIssuer is used when USER_SVC_TOTP_ISSUER is not set
+const defaultTOTPIssuer = "Showcase"
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP generates the secret of a new authenticator app of the caller,
+// it protects the logins once confirmed with ConfirmTOTP
+func (s *UserServer) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.db.FindByID(identity.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	sealed, err := s.sealer.Seal(secret)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.SavePendingTOTP(&model.TOTPFactor{UserID: user.UUID, Secret: sealed}); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.EnrollTOTPResponse{
+		Secret: secret,
+		Uri:    totp.URI(s.totpIssuer(), user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables the pending authenticator app of the caller with its
+// first code and returns new recovery codes
+func (s *UserServer) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateConfirmTOTP(req); err != nil {
+		return nil, err
+	}
+	factor, err := s.db.FindTOTPFactor(identity.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if factor.Confirmed() {
+		return nil, s.toStatus(repo.ErrTwoFactorEnabled)
+	}
+	now := time.Now()
+	var step int64
+	err = s.throttleCodes(identity.UserID, now, func() (err error) {
+		step, err = s.validateTOTP(factor, req.Code, now)
+		return err
+	})
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	recovery, records, err := newRecoveryCodes(identity.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.ConfirmTOTP(identity.UserID, step, records, now); err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("two-factor authentication enabled for user %s", identity.UserID)
+	return &pb.ConfirmTOTPResponse{RecoveryCodes: recovery}, nil
+}
+
+// DisableTOTP removes the authenticator app and the recovery codes of the
+// caller, given one of their codes
+func (s *UserServer) DisableTOTP(ctx context.Context, req *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateDisableTOTP(req); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = s.throttleCodes(identity.UserID, now, func() error {
+		return s.checkSecondFactor(identity.UserID, req.Code, now)
+	})
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.DeleteTwoFactor(identity.UserID); err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.log.Printf("two-factor authentication disabled for user %s", identity.UserID)
+	return &pb.DisableTOTPResponse{}, nil
+}
+
+// VerifySecondFactor exchanges the challenge token of a login and a code for
+// the tokens
+func (s *UserServer) VerifySecondFactor(ctx context.Context, req *pb.VerifySecondFactorRequest) (*pb.LoginResponse, error) {
+	if err := utils.ValidateVerifySecondFactor(req); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	hash := hashToken(req.ChallengeToken)
+	challenge, err := s.db.AttemptLoginChallenge(hash, now, challengeMaxAttempts)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	err = s.checkSecondFactor(challenge.UserID, req.Code, now)
+	if errors.Is(err, repo.ErrInvalidCode) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if err := s.db.CompleteLoginChallenge(hash, now); err != nil {
+		return nil, s.toStatus(err)
+	}
+	user, err := s.db.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.LoginResponse{
+		Token:        tokens.access,
+		RefreshToken: tokens.refresh,
+		ExpiresIn:    tokens.expiresIn,
+	}, nil
+}
+
+// loginChallenge starts the second step of the login of a user with a
+// confirmed authenticator app and returns the challenge token, or an empty
+// token when the user did not enable two-factor authentication
+func (s *UserServer) loginChallenge(user *model.User) (string, error) {
+	factor, err := s.db.FindTOTPFactor(user.UUID)
+	if errors.Is(err, repo.ErrTwoFactorNotEnabled) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !factor.Confirmed() {
+		return "", nil
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return "", err
+	}
+	err = s.db.CreateLoginChallenge(&model.LoginChallenge{
+		TokenHash: hashToken(secret),
+		UserID:    user.UUID,
+		ExpiresAt: time.Now().Add(challengeExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// checkSecondFactor accepts a code of the confirmed authenticator app of the
+// user or one of their recovery codes, the code cannot be used again
+func (s *UserServer) checkSecondFactor(userID string, code string, now time.Time) error {
+	factor, err := s.db.FindTOTPFactor(userID)
+	if err != nil {
+		return err
+	}
+	if !factor.Confirmed() {
+		return repo.ErrTwoFactorNotEnabled
+	}
+	if len(code) == totp.Digits {
+		step, err := s.validateTOTP(factor, code, now)
+		if err != nil {
+			return err
+		}
+		return s.db.UseTOTPStep(userID, step)
+	}
+	return s.db.UseRecoveryCode(userID, hashToken(normalizeRecoveryCode(code)), now)
+}
+
+// throttleCodes runs the check of a code presented by a signed in user,
+// locking the codes of the user after too many wrong ones
+func (s *UserServer) throttleCodes(userID string, now time.Time, check func() error) error {
+	key := "totp:" + userID
+	until, err := s.db.LockedUntil([]string{key}, now)
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		return lockedError("too many wrong codes, try again later", until.Sub(now))
+	}
+	err = check()
+	if errors.Is(err, repo.ErrInvalidCode) {
+		if _, recordErr := s.db.RecordLoginFailure(key, codePolicy, &model.AuditEvent{UserID: userID}, now); recordErr != nil {
+			return recordErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return s.db.ResetLoginFailures(key)
+}
+
+// validateTOTP returns the time step matched by a code of the factor
+func (s *UserServer) validateTOTP(factor *model.TOTPFactor, code string, now time.Time) (int64, error) {
+	secret, err := s.sealer.Open(factor.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := totp.Validate(secret, code, now)
+	if !ok {
+		return 0, repo.ErrInvalidCode
+	}
+	return step, nil
+}
+
+// newRecoveryCodes returns random recovery codes and the records of their hashes
+func newRecoveryCodes(userID string) ([]string, []*model.RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*model.RecoveryCode, 0, recoveryCodeCount)
+	buf := make([]byte, recoveryCodeSize)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(buf))
+		// grouped by four characters to be copied by hand
+		var grouped strings.Builder
+		for j := 0; j < len(code); j += 4 {
+			if j > 0 {
+				grouped.WriteByte('-')
+			}
+			grouped.WriteString(code[j : j+4])
+		}
+		codes = append(codes, grouped.String())
+		records = append(records, &model.RecoveryCode{UserID: userID, CodeHash: hashToken(code)})
+	}
+	return codes, records, nil
+}
+
+// normalizeRecoveryCode drops the separators and the case of a recovery code
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// totpIssuer returns the name of the accounts in the authenticator apps
+func (s *UserServer) totpIssuer() string {
+	if s.settings.TwoFactor.Issuer != "" {
+		return s.settings.TwoFactor.Issuer
+	}
+	return defaultTOTPIssuer
+}
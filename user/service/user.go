@@ -3,20 +3,27 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/iamvasanth07/showcase/common"
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/pagetoken"
 	pb "github.com/iamvasanth07/showcase/common/protos/user"
 	"github.com/iamvasanth07/showcase/user/config"
+	"github.com/iamvasanth07/showcase/user/keys"
+	"github.com/iamvasanth07/showcase/user/mailer"
 	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/oidc"
 	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/totp"
 	"github.com/iamvasanth07/showcase/user/utils"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -30,20 +37,69 @@ type IUserService interface {
 	GetAll(context.Context, *pb.GetAllUserRequest) (*pb.GetAllUserResponse, error)
 	Get(context.Context, *pb.GetUserRequest) (*pb.GetUserResponse, error)
 	Login(context.Context, *pb.LoginRequest) (*pb.LoginResponse, error)
+	VerifySecondFactor(context.Context, *pb.VerifySecondFactorRequest) (*pb.LoginResponse, error)
+	StartOIDCLogin(context.Context, *pb.StartOIDCLoginRequest) (*pb.StartOIDCLoginResponse, error)
+	CompleteOIDCLogin(context.Context, *pb.CompleteOIDCLoginRequest) (*pb.LoginResponse, error)
+	EnrollTOTP(context.Context, *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error)
+	ConfirmTOTP(context.Context, *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error)
+	DisableTOTP(context.Context, *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error)
+	AssignRole(context.Context, *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error)
+	RevokeRole(context.Context, *pb.RevokeRoleRequest) (*pb.RevokeRoleResponse, error)
+	ListRoles(context.Context, *pb.ListRolesRequest) (*pb.ListRolesResponse, error)
+	Refresh(context.Context, *pb.RefreshRequest) (*pb.RefreshResponse, error)
+	Logout(context.Context, *pb.LogoutRequest) (*pb.LogoutResponse, error)
+	ListRevokedTokens(context.Context, *pb.ListRevokedTokensRequest) (*pb.ListRevokedTokensResponse, error)
+	GetJWKS(context.Context, *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error)
+	RequestPasswordReset(context.Context, *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error)
+	ResetPassword(context.Context, *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error)
+	UnlockUser(context.Context, *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error)
+	CreatePersonalAccessToken(context.Context, *pb.CreatePersonalAccessTokenRequest) (*pb.CreatePersonalAccessTokenResponse, error)
+	ListPersonalAccessTokens(context.Context, *pb.ListPersonalAccessTokensRequest) (*pb.ListPersonalAccessTokensResponse, error)
+	RevokePersonalAccessToken(context.Context, *pb.RevokePersonalAccessTokenRequest) (*pb.RevokePersonalAccessTokenResponse, error)
+	ListSessions(context.Context, *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error)
+	RevokeSession(context.Context, *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error)
+	RevokeAllOtherSessions(context.Context, *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeAllOtherSessionsResponse, error)
+	ValidatePersonalAccessToken(context.Context, *pb.ValidatePersonalAccessTokenRequest) (*pb.ValidatePersonalAccessTokenResponse, error)
+	VerifyEmail(context.Context, *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error)
+	ResendVerification(context.Context, *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error)
 }
 
 type UserServer struct {
-	db       *repo.UserRepo
+	db     *repo.UserRepo
+	keys   *keys.KeyRing
+	mailer mailer.Mailer
+	// sealer encrypts the secrets of the authenticator apps
+	sealer   *totp.Sealer
 	log      *log.Logger
 	settings *config.Settings
+	// pageTokens signs the page tokens of the user listing
+	pageTokens *pagetoken.Codec
+	authz      *auth.Authorizer
+	// providers are the external identity providers by name
+	providers map[string]*oidc.Provider
+	// identities stores the logins with the providers, the database
+	identities identityStore
+	// resetRequests holds the addresses of the password resets to create
+	resetRequests chan string
 	pb.UnimplementedUserServiceServer
 }
 
-func NewUserServer(db *repo.UserRepo, log *log.Logger, settings *config.Settings) *UserServer {
+// defaultPageSize is the page size of the listings without a limit
+const defaultPageSize = 20
+
+func NewUserServer(db *repo.UserRepo, keyRing *keys.KeyRing, mail mailer.Mailer, sealer *totp.Sealer, log *log.Logger, settings *config.Settings) *UserServer {
 	return &UserServer{
-		db:       db,
-		log:      log,
-		settings: settings,
+		db:            db,
+		keys:          keyRing,
+		mailer:        mail,
+		sealer:        sealer,
+		log:           log,
+		settings:      settings,
+		pageTokens:    pagetoken.NewCodec(settings.PageToken.Secret),
+		authz:         auth.NewAuthorizer(),
+		providers:     newOIDCProviders(settings),
+		identities:    db,
+		resetRequests: make(chan string, resetQueueSize),
 	}
 }
 
@@ -53,13 +109,16 @@ func (s *UserServer) Create(ctx context.Context, req *pb.CreateUserRequest) (*pb
 	}
 	user := ProtoToUser(req.User)
 	user.Password = req.User.Password
-	err := s.db.Create(user)
+	// the account starts unverified, the verification link goes out with it
+	err := s.db.CreateWithEmail(user, func(created *model.User) (*model.OutboxEmail, error) {
+		return s.verificationEmail(created), nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
 	}
 	getUser, err := s.db.FindByEmail(user.Email)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
 	}
 	return &pb.CreateUserResponse{
 		User: UserToProto(getUser),
@@ -67,18 +126,40 @@ func (s *UserServer) Create(ctx context.Context, req *pb.CreateUserRequest) (*pb
 }
 
 func (s *UserServer) Update(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	if err := utils.ValidateUserUpdate(req.User); err != nil {
+	if err := utils.ValidateUserUpdate(req); err != nil {
+		return nil, err
+	}
+	if _, err := s.authz.AuthorizeOwner(ctx, req.Id, auth.PermUserUpdateAny); err != nil {
 		return nil, err
 	}
 
-	user := ProtoToUser(req.User)
-	err := s.db.Update(user)
+	user, err := s.db.FindByID(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
+	}
+	// a new email address has to be verified again
+	emailChanged := user.Email != req.User.Email
+	user.Email = req.User.Email
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+	}
+	user.FirstName = req.User.FirstName
+	user.LastName = req.User.LastName
+	user.Phone = req.User.Phone
+	if req.User.Username != "" {
+		user.Username = req.User.Username
+	}
+	if emailChanged {
+		err = s.db.UpdateWithEmail(user, s.verificationEmail(user))
+	} else {
+		err = s.db.Update(user)
 	}
-	getUser, err := s.db.FindByEmail(user.Email)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
+	}
+	getUser, err := s.db.FindByID(user.UUID)
+	if err != nil {
+		return nil, s.toStatus(err)
 	}
 	return &pb.UpdateUserResponse{
 		User: UserToProto(getUser),
@@ -92,7 +173,7 @@ func (s *UserServer) Get(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUs
 	}
 	user, err := s.db.FindByID(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
 	}
 	return &pb.GetUserResponse{
 		User: UserToProto(user),
@@ -103,9 +184,12 @@ func (s *UserServer) Delete(ctx context.Context, req *pb.DeleteUserRequest) (*pb
 	if err := utils.ValidateUserDelete(req.Id); err != nil {
 		return nil, err
 	}
+	if _, err := s.authz.AuthorizeOwner(ctx, req.Id, auth.PermUserDeleteAny); err != nil {
+		return nil, err
+	}
 	err := s.db.Delete(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
 	}
 	return &pb.DeleteUserResponse{
 		Id: req.Id,
@@ -117,9 +201,36 @@ func (s *UserServer) GetAll(ctx context.Context, req *pb.GetAllUserRequest) (*pb
 	if err := utils.ValidateUserGetAll(req); err != nil {
 		return nil, err
 	}
-	users, err := s.db.FindAll(req.Paginate.Page, req.Paginate.Limit)
+	page, limit := req.Paginate.Page, req.Paginate.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+
+	// one more user tells whether there is a next page
+	var users []*model.User
+	var err error
+	if req.Paginate.PageToken != "" {
+		token, decodeErr := s.pageTokens.Decode(req.Paginate.PageToken, "")
+		if decodeErr != nil {
+			return nil, status.Error(codes.InvalidArgument, decodeErr.Error())
+		}
+		page = 0
+		users, err = s.db.FindAfter(&repo.Cursor{Key: token.Key, ID: token.ID}, limit+1)
+	} else {
+		users, err = s.db.FindAll(page, limit+1)
+	}
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
+	}
+	total, err := s.db.Count()
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	var nextPageToken string
+	if len(users) > int(limit) {
+		users = users[:limit]
+		cursor := repo.CursorOf(users[len(users)-1])
+		nextPageToken = s.pageTokens.Encode(&pagetoken.Token{Key: cursor.Key, ID: cursor.ID})
 	}
 	var res []*pb.User
 	var meta *pb.Metadata
@@ -127,11 +238,11 @@ func (s *UserServer) GetAll(ctx context.Context, req *pb.GetAllUserRequest) (*pb
 		res = append(res, UserToProto(user))
 	}
 	meta = &pb.Metadata{
-		Total: int32(len(res)),
-		Page:  req.Paginate.Page,
-		Limit: req.Paginate.Limit,
+		Total: int32(total),
+		Page:  page,
+		Limit: limit,
 	}
-	return &pb.GetAllUserResponse{Users: res, Metadata: meta}, nil
+	return &pb.GetAllUserResponse{Users: res, Metadata: meta, NextPageToken: nextPageToken}, nil
 }
 
 // Login user return token
@@ -139,39 +250,48 @@ func (s *UserServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Login
 	if err := utils.ValidateUserLogin(req); err != nil {
 		return nil, err
 	}
-	user, err := s.db.FindByEmail(req.Email)
-	if err != nil {
-		return nil, err
-	}
-	if user == nil {
-		return nil, status.Error(codes.NotFound, "User not found")
-	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, status.Error(codes.Unauthenticated, "Invalid credentials")
-	}
-	token, err := s.generateJWTToken(user)
+	user, err := s.authenticatePassword(ctx, req.Email, req.Password)
 	if err != nil {
 		return nil, err
 	}
-	return &pb.LoginResponse{
-		Token: token,
-	}, nil
+	return s.completeLogin(ctx, user)
 }
 
-// function to generate JWT token with expiry time
-func (s *UserServer) generateJWTToken(user *model.User) (string, error) {
-	claims := &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(time.Hour * time.Duration(s.settings.JWT.Expiry)).Unix(),
-		Issuer:    user.Email,
+// function to generate JWT token with the jti and expiry time, carrying the
+// roles and permissions of the user
+func (s *UserServer) generateJWTToken(user *model.User, jti string, expiresAt time.Time) (string, error) {
+	roles, err := s.db.FindUserRoles(user.UUID)
+	if err != nil {
+		return "", err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.settings.JWT.Secret))
+	roleNames, permissions := grants(roles)
+	now := time.Now()
+	claims := &auth.Claims{
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified(),
+		Roles:         roleNames,
+		Permissions:   permissions,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			Subject:   user.UUID,
+			Issuer:    s.settings.JWT.Issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	key := s.keys.Active()
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SigningKey())
 }
 
 func RunServer() {
 
 	logger := log.New(os.Stdout, "user-service: ", log.LstdFlags)
 	settings := config.GetSettings()
+	if missing := settings.MissingSecrets(); len(missing) > 0 {
+		log.Fatalf("refusing to start without the secrets %s", strings.Join(missing, ", "))
+	}
 	logger.Println("Initializing user service with settings...")
 	logger.Printf("%v, %v, %v", settings.Database, settings.Server, settings.Logger)
 	conn, err := initDB(settings)
@@ -185,9 +305,26 @@ func RunServer() {
 	}
 
 	db := repo.NewUserRepo(conn)
+	if err := db.EnsureRoles(model.DefaultRoles); err != nil {
+		log.Fatalf("failed to create the default roles: %v", err)
+	}
+
+	keyRing, err := initKeys(settings, logger)
+	if err != nil {
+		log.Fatalf("failed to load the signing keys: %v", err)
+	}
+	go keyRing.Run(context.Background(), keyRotationCheck)
+	mail, err := mailer.New(settings)
+	if err != nil {
+		log.Fatalf("failed to initialize mailer: %v", err)
+	}
+	sealer, err := totp.NewSealer(settings.TwoFactor.Key)
+	if err != nil {
+		log.Fatalf("failed to initialize the totp sealer: %v", err)
+	}
 
 	// Starting gRPC server
-	runGRPCServer(settings, db, logger)
+	runGRPCServer(settings, db, keyRing, mail, sealer, logger)
 
 }
 
@@ -198,13 +335,54 @@ func initDB(settings *config.Settings) (*gorm.DB, error) {
 }
 
 func migrateDB(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&model.User{},
+		&model.Role{},
+		&model.RolePermission{},
+		&model.UserRole{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.PasswordResetToken{},
+		&model.OutboxEmail{},
+		&model.TOTPFactor{},
+		&model.RecoveryCode{},
+		&model.LoginChallenge{},
+		&model.LoginThrottle{},
+		&model.AuditEvent{},
+		&model.PersonalAccessToken{},
+		&model.ExternalIdentity{},
+		&model.OIDCLogin{},
+		&model.Session{},
 	)
+	if err != nil {
+		return err
+	}
+	// the phone index covered the empty numbers before the external sign ups
+	if db.Migrator().HasIndex(&model.User{}, "idx_users_phone") {
+		if err := db.Migrator().DropIndex(&model.User{}, "idx_users_phone"); err != nil {
+			return err
+		}
+	}
+	// the email addresses are looked up regardless of their case
+	return db.Exec(`CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email))`).Error
 }
 
-func runGRPCServer(settings *config.Settings, db *repo.UserRepo, logger *log.Logger) {
-	userServer := NewUserServer(db, logger, settings)
+func runGRPCServer(settings *config.Settings, db *repo.UserRepo, keyRing *keys.KeyRing, mail mailer.Mailer, sealer *totp.Sealer, logger *log.Logger) {
+	userServer := NewUserServer(db, keyRing, mail, sealer, logger, settings)
+	go userServer.CleanupExpiredTokens(context.Background(), time.Hour)
+	go userServer.DeliverEmails(context.Background(), outboxInterval)
+	go userServer.ProcessPasswordResets(context.Background())
+	if email := settings.Bootstrap.AdminEmail; email != "" {
+		user, err := db.FindByEmail(email)
+		switch {
+		case err == nil:
+			userServer.bootstrapAdmin(user)
+		case errors.Is(err, repo.ErrNotFound):
+			logger.Printf("the admin role will be granted to %s once the address is verified", email)
+		default:
+			log.Fatalf("failed to bootstrap the admin: %v", err)
+		}
+	}
 	var opts []grpc.ServerOption
 	s := grpc.NewServer(opts...)
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", settings.Server.GrpcHost, settings.Server.GrcpPort))
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/user/model"
+	"github.com/iamvasanth07/showcase/user/repo"
+	"github.com/iamvasanth07/showcase/user/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultVerificationExpiry is used when USER_SVC_VERIFICATION_EXPIRY is not set
+const defaultVerificationExpiry = 48 * time.Hour
+
+// verificationLimit caps the verification emails a user can ask for
+var verificationLimit = repo.EmailLimit{
+	Interval: time.Minute,
+	Max:      5,
+	Window:   24 * time.Hour,
+}
+
+// verificationClaims are the signed content of a verification token, the
+// token only verifies the email address it was sent to
+type verificationClaims struct {
+	UserID    string `json:"u"`
+	Email     string `json:"e"`
+	ExpiresAt int64  `json:"x"`
+}
+
+// VerifyEmail marks the email address of a verification token verified
+func (s *UserServer) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if err := utils.ValidateVerifyEmail(req); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	claims, err := s.parseVerificationToken(req.Token, now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	user, err := s.db.VerifyEmail(claims.UserID, claims.Email, now)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	s.bootstrapAdmin(user)
+	return &pb.VerifyEmailResponse{User: UserToProto(user)}, nil
+}
+
+// ResendVerification emails a new verification link to the caller
+func (s *UserServer) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.db.FindByID(identity.UserID)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if user.EmailVerified() {
+		return nil, status.Error(codes.FailedPrecondition, "email address is already verified")
+	}
+	if err := s.db.QueueEmail(s.verificationEmail(user), verificationLimit, time.Now()); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.ResendVerificationResponse{}, nil
+}
+
+// verificationEmail returns the email carrying the verification link of the
+// current email address of the user
+func (s *UserServer) verificationEmail(user *model.User) *model.OutboxEmail {
+	expiry := s.verificationExpiry()
+	token := s.verificationToken(&verificationClaims{
+		UserID:    user.UUID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(expiry).Unix(),
+	})
+	return &model.OutboxEmail{
+		Recipient: user.Email,
+		UserID:    user.UUID,
+		Kind:      model.EmailVerification,
+		Subject:   "Verify your Showcase email address",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Open the link below within %d hours to verify the email address of your Showcase account:\n\n"+
+			"%s\n\n"+
+			"If you did not sign up for Showcase, ignore this email.\n",
+			user.FirstName, int(expiry.Hours()), s.verificationLink(token)),
+	}
+}
+
+// verificationToken returns the signed form of the claims
+func (s *UserServer) verificationToken(claims *verificationClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.signVerification(encoded)
+}
+
+// parseVerificationToken verifies the signature and the expiry of a token
+func (s *UserServer) parseVerificationToken(token string, now time.Time) (*verificationClaims, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.signVerification(encoded))) {
+		return nil, repo.ErrInvalidVerificationToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, repo.ErrInvalidVerificationToken
+	}
+	claims := &verificationClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil || now.Unix() > claims.ExpiresAt {
+		return nil, repo.ErrInvalidVerificationToken
+	}
+	return claims, nil
+}
+
+func (s *UserServer) signVerification(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(s.settings.Verification.Secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verificationLink returns the link of the verification page carrying the token
+func (s *UserServer) verificationLink(token string) string {
+	link, err := url.Parse(s.settings.Verification.URL)
+	if err != nil || s.settings.Verification.URL == "" {
+		return "Verification token: " + token
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// verificationExpiry returns the lifetime of the verification links
+func (s *UserServer) verificationExpiry() time.Duration {
+	if s.settings.Verification.Expiry > 0 {
+		return time.Duration(s.settings.Verification.Expiry) * time.Hour
+	}
+	return defaultVerificationExpiry
+}
@@ -0,0 +1,64 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrUnsealed is returned for sealed secrets that cannot be opened with the key
+var ErrUnsealed = errors.New("cannot open the sealed totp secret")
+
+// ErrEmptyKey is returned for an empty passphrase, the secrets would be
+// sealed with a well-known key
+var ErrEmptyKey = errors.New("the totp sealing key is empty")
+
+// Sealer encrypts the secrets stored in the database, a leak of the database
+// alone does not give away the codes
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer returns a sealer encrypting with a key derived from the passphrase
+func NewSealer(passphrase string) (*Sealer, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyKey
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal returns the encrypted form of a secret
+func (s *Sealer) Seal(secret string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open returns the secret of a sealed secret
+func (s *Sealer) Open(sealed string) (string, error) {
+	buf, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(buf) < s.aead.NonceSize() {
+		return "", ErrUnsealed
+	}
+	nonce, ciphertext := buf[:s.aead.NonceSize()], buf[s.aead.NonceSize():]
+	secret, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrUnsealed
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,106 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890"
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238(t *testing.T) {
+	// the 8 digit codes of Appendix B, truncated to their last 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() at %d = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code() at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeRejectsInvalidSecrets(t *testing.T) {
+	for _, secret := range []string{"not base32!", "ABC1"} {
+		if _, err := Code(secret, 1); err == nil {
+			t.Errorf("Code(%q) succeeded", secret)
+		}
+	}
+	lower, err := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upper, _ := Code(rfcSecret, 1); lower != upper {
+		t.Errorf("Code() of a lowercase secret = %s, want %s", lower, upper)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+	code := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		code   string
+		at     time.Time
+		want   bool
+		wantAt int64
+	}{
+		{"current step", code(step), now, true, step},
+		{"previous step within the drift", code(step - 1), now, true, step - 1},
+		{"next step within the drift", code(step + 1), now, true, step + 1},
+		{"end of the step", code(step), time.Unix((step+1)*30-1, 0), true, step},
+		{"two steps late", code(step - 2), now, false, 0},
+		{"two steps early", code(step + 2), now, false, 0},
+		{"code of the previous step after the drift", code(step), now.Add(2 * Period), false, 0},
+		{"wrong code", "000000", now, false, 0},
+		{"short code", code(step)[:5], now, false, 0},
+		{"long code", code(step) + "0", now, false, 0},
+		{"empty code", "", now, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Validate(rfcSecret, tt.code, tt.at)
+			if ok != tt.want || got != tt.wantAt {
+				t.Errorf("Validate() = %d, %v, want %d, %v", got, ok, tt.wantAt, tt.want)
+			}
+		})
+	}
+	if _, ok := Validate("not base32!", code(step), now); ok {
+		t.Error("Validate() accepted a code with an invalid secret")
+	}
+}
+
+func TestValidateReturnsTheStepOfReplayedCodes(t *testing.T) {
+	// Validate accepts a code again within the drift, the callers reject the
+	// replay through the step it matched
+	now := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, Step(now))
+	first, ok := Validate(rfcSecret, code, now)
+	if !ok {
+		t.Fatal("Validate() rejected the current code")
+	}
+	second, ok := Validate(rfcSecret, code, now.Add(Period))
+	if !ok || second != first {
+		t.Errorf("replayed code matched %d, %v, want the step %d", second, ok, first)
+	}
+}
@@ -3,7 +3,9 @@ package utils
 import (
 	"fmt"
 
+	"github.com/iamvasanth07/showcase/common/auth"
 	pb "github.com/iamvasanth07/showcase/common/protos/user"
+	"github.com/iamvasanth07/showcase/common/validation"
 	"github.com/iamvasanth07/showcase/user/model"
 )
 
@@ -49,11 +51,16 @@ func ValidateUserName(username string) error {
 	return nil
 }
 
-// ValidateName validates Firstname and Lastname
-func ValidateName(firstName string, lastName string) error {
+// ValidateFirstName validates firstname
+func ValidateFirstName(firstName string) error {
 	if firstName == "" {
 		return fmt.Errorf("firstname is required")
 	}
+	return nil
+}
+
+// ValidateLastName validates lastname
+func ValidateLastName(lastName string) error {
 	if lastName == "" {
 		return fmt.Errorf("lastname is required")
 	}
@@ -84,88 +91,258 @@ func ValidateID(id string) error {
 
 // ValidateUserCreate validates user
 func ValidateUserCreate(user *pb.User) error {
+	v := &validation.Violations{}
 	if user == nil {
-		return fmt.Errorf("user is required")
-	}
-	if err := ValidateEmail(user.Email); err != nil {
-		return err
-	}
-	if err := ValidatePassword(user.Password); err != nil {
-		return err
-	}
-	if err := ValidateUserName(user.Username); err != nil {
-		return err
-	}
-	if err := ValidateName(user.FirstName, user.LastName); err != nil {
-		return err
-	}
-	if err := ValidatePhone(user.Phone); err != nil {
-		return err
-	}
-	return nil
+		v.Add("user", fmt.Errorf("user is required"))
+		return v.Err()
+	}
+	v.Add("email", ValidateEmail(user.Email))
+	v.Add("password", ValidatePassword(user.Password))
+	v.Add("username", ValidateUserName(user.Username))
+	v.Add("firstName", ValidateFirstName(user.FirstName))
+	v.Add("lastName", ValidateLastName(user.LastName))
+	v.Add("phone", ValidatePhone(user.Phone))
+	return v.Err()
 }
 
 // ValidateUserUpdate validates user update
-func ValidateUserUpdate(user *pb.User) error {
-
-	if user == nil {
-		return fmt.Errorf("user is required")
-	}
-	if err := ValidateEmail(user.Email); err != nil {
-		return err
-	}
-	if err := ValidateName(user.FirstName, user.LastName); err != nil {
-		return err
-	}
-	if err := ValidatePhone(user.Phone); err != nil {
-		return err
-	}
-	return nil
+func ValidateUserUpdate(req *pb.UpdateUserRequest) error {
+	v := &validation.Violations{}
+	if req == nil || req.User == nil {
+		v.Add("user", fmt.Errorf("user is required"))
+		return v.Err()
+	}
+	v.Add("id", ValidateID(req.Id))
+	v.Add("email", ValidateEmail(req.User.Email))
+	v.Add("firstName", ValidateFirstName(req.User.FirstName))
+	v.Add("lastName", ValidateLastName(req.User.LastName))
+	// the users signed up with an external provider have no phone number
+	if req.User.Phone != "" {
+		v.Add("phone", ValidatePhone(req.User.Phone))
+	}
+	return v.Err()
 }
 
 // ValidateUserDelete validates user delete
 func ValidateUserDelete(id string) error {
-	if err := ValidateID(id); err != nil {
-		return err
-	}
-	return nil
+	v := &validation.Violations{}
+	v.Add("id", ValidateID(id))
+	return v.Err()
 }
 
 // ValidateUserGet validates user get
 func ValidateUserGet(id string) error {
-	if err := ValidateID(id); err != nil {
-		return err
-	}
-	return nil
+	v := &validation.Violations{}
+	v.Add("id", ValidateID(id))
+	return v.Err()
 }
 
 // ValidateUserGetAll validates user get all
 func ValidateUserGetAll(req *pb.GetAllUserRequest) error {
+	v := &validation.Violations{}
 	if req == nil {
-		return fmt.Errorf("body cannot be empty")
+		v.Add("body", fmt.Errorf("body cannot be empty"))
+		return v.Err()
 	}
 	if req.Paginate == nil {
-		return fmt.Errorf("paginate options cannot be empty")
+		v.Add("paginate", fmt.Errorf("paginate options cannot be empty"))
+		return v.Err()
 	}
 	if req.Paginate.Limit < 0 {
-		return fmt.Errorf("invalid limit")
+		v.Add("paginate.limit", fmt.Errorf("invalid limit"))
 	}
 	if req.Paginate.Page < 0 {
-		return fmt.Errorf("invalid page")
+		v.Add("paginate.page", fmt.Errorf("invalid page"))
 	}
-	return nil
+	return v.Err()
 }
 
 // ValidateUserLogin validates user login
 func ValidateUserLogin(req *pb.LoginRequest) error {
+	v := &validation.Violations{}
 	if req == nil {
-		return fmt.Errorf("body cannot be empty")
+		v.Add("body", fmt.Errorf("body cannot be empty"))
+		return v.Err()
 	}
-	if err := ValidateEmail(req.Email); err != nil {
-		return err
+	v.Add("email", ValidateEmail(req.Email))
+	v.Add("password", ValidatePassword(req.Password))
+	return v.Err()
+}
+
+// ValidateRoleName validates role name
+func ValidateRoleName(name string) error {
+	if name == "" {
+		return fmt.Errorf("role is required")
 	}
-	if err := ValidatePassword(req.Password); err != nil {
-		return err
+	if !model.RoleNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid role")
 	}
 	return nil
 }
+
+// ValidateRoleChange validates the user and the role of a role assignment or revocation
+func ValidateRoleChange(userID string, role string) error {
+	v := &validation.Violations{}
+	v.Add("userId", ValidateID(userID))
+	v.Add("role", ValidateRoleName(role))
+	return v.Err()
+}
+
+// ValidateListRoles validates list roles
+func ValidateListRoles(req *pb.ListRolesRequest) error {
+	v := &validation.Violations{}
+	if req.UserId != "" {
+		v.Add("userId", ValidateID(req.UserId))
+	}
+	return v.Err()
+}
+
+// ValidateRequestPasswordReset validates password reset request
+func ValidateRequestPasswordReset(req *pb.RequestPasswordResetRequest) error {
+	v := &validation.Violations{}
+	v.Add("email", ValidateEmail(req.Email))
+	return v.Err()
+}
+
+// ValidateResetPassword validates password reset
+func ValidateResetPassword(req *pb.ResetPasswordRequest) error {
+	v := &validation.Violations{}
+	if req.Token == "" {
+		v.Add("token", fmt.Errorf("token is required"))
+	}
+	v.Add("password", ValidatePassword(req.Password))
+	return v.Err()
+}
+
+// ValidateVerifyEmail validates email verification
+func ValidateVerifyEmail(req *pb.VerifyEmailRequest) error {
+	v := &validation.Violations{}
+	if req.Token == "" {
+		v.Add("token", fmt.Errorf("token is required"))
+	}
+	return v.Err()
+}
+
+// ValidateSecondFactorCode validates a code of an authenticator app or a recovery code
+func ValidateSecondFactorCode(code string) error {
+	if code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if len(code) > 32 {
+		return fmt.Errorf("code is too long")
+	}
+	return nil
+}
+
+// ValidateConfirmTOTP validates the confirmation of an authenticator app
+func ValidateConfirmTOTP(req *pb.ConfirmTOTPRequest) error {
+	v := &validation.Violations{}
+	v.Add("code", ValidateSecondFactorCode(req.Code))
+	return v.Err()
+}
+
+// ValidateDisableTOTP validates two-factor authentication disabling
+func ValidateDisableTOTP(req *pb.DisableTOTPRequest) error {
+	v := &validation.Violations{}
+	v.Add("code", ValidateSecondFactorCode(req.Code))
+	return v.Err()
+}
+
+// ValidateVerifySecondFactor validates the second step of a login
+func ValidateVerifySecondFactor(req *pb.VerifySecondFactorRequest) error {
+	v := &validation.Violations{}
+	if req.ChallengeToken == "" {
+		v.Add("challengeToken", fmt.Errorf("challenge token is required"))
+	}
+	v.Add("code", ValidateSecondFactorCode(req.Code))
+	return v.Err()
+}
+
+// ValidateUnlockUser validates user unlocking
+func ValidateUnlockUser(req *pb.UnlockUserRequest) error {
+	v := &validation.Violations{}
+	v.Add("userId", ValidateID(req.UserId))
+	return v.Err()
+}
+
+// maxTokenExpiryDays is the longest lifetime of the personal access tokens
+const maxTokenExpiryDays = 365
+
+// ValidateScopes validates the scopes of a personal access token
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		known := false
+		for _, s := range auth.Scopes {
+			if s == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+		if seen[scope] {
+			return fmt.Errorf("duplicate scope %q", scope)
+		}
+		seen[scope] = true
+	}
+	return nil
+}
+
+// ValidateCreatePersonalAccessToken validates personal access token creation
+func ValidateCreatePersonalAccessToken(req *pb.CreatePersonalAccessTokenRequest) error {
+	v := &validation.Violations{}
+	if !model.TokenNameRegex.MatchString(req.Name) {
+		v.Add("name", fmt.Errorf("name must be 1 to 64 letters, digits, spaces, dots, dashes or underscores"))
+	}
+	v.Add("scopes", ValidateScopes(req.Scopes))
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > maxTokenExpiryDays {
+		v.Add("expiresInDays", fmt.Errorf("expiresInDays must be between 1 and %d", maxTokenExpiryDays))
+	}
+	return v.Err()
+}
+
+// ValidateRevokePersonalAccessToken validates personal access token revocation
+func ValidateRevokePersonalAccessToken(req *pb.RevokePersonalAccessTokenRequest) error {
+	v := &validation.Violations{}
+	v.Add("id", ValidateID(req.Id))
+	return v.Err()
+}
+
+// ValidateRevokeSession validates session revocation
+func ValidateRevokeSession(req *pb.RevokeSessionRequest) error {
+	v := &validation.Violations{}
+	v.Add("id", ValidateID(req.Id))
+	return v.Err()
+}
+
+// ValidateStartOIDCLogin validates the start of a login with an external provider
+func ValidateStartOIDCLogin(req *pb.StartOIDCLoginRequest) error {
+	v := &validation.Violations{}
+	if req.Provider == "" {
+		v.Add("provider", fmt.Errorf("provider is required"))
+	}
+	if len(req.LoginHint) > 254 {
+		v.Add("loginHint", fmt.Errorf("login hint is too long"))
+	}
+	return v.Err()
+}
+
+// ValidateCompleteOIDCLogin validates the callback of a login with an external provider
+func ValidateCompleteOIDCLogin(req *pb.CompleteOIDCLoginRequest) error {
+	v := &validation.Violations{}
+	if req.Provider == "" {
+		v.Add("provider", fmt.Errorf("provider is required"))
+	}
+	if req.Code == "" {
+		v.Add("code", fmt.Errorf("code is required"))
+	}
+	if req.State == "" {
+		v.Add("state", fmt.Errorf("state is required"))
+	}
+	return v.Err()
+}
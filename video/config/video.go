@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 )
 
 type server struct {
@@ -24,16 +25,72 @@ type logger struct {
 	Level string
 }
 
+// S3 holds the settings of an S3 compatible object store
+type S3 struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+type upload struct {
+	Expiry int
+}
+
+type processing struct {
+	Transcoder  string
+	Prober      string
+	FFmpegPath  string
+	FFprobePath string
+	Workers     int
+	MaxAttempts int
+}
+
+type playback struct {
+	Secret string
+	TTL    int
+}
+
+// channelService is the address of the channel grpc service
+type channelService struct {
+	GrpcHost string
+	GrcpPort string
+}
+
+type pageToken struct {
+	Secret string
+}
+
+type storage struct {
+	Backend   string
+	LocalPath string
+	S3        *S3
+}
+
 // Settings struct
 type Settings struct {
-	Server   *server
-	Database *database
-	Logger   *logger
+	Server     *server
+	Database   *database
+	Logger     *logger
+	Storage    *storage
+	Upload     *upload
+	Processing *processing
+	Playback   *playback
+	PageToken  *pageToken
+	Channel    *channelService
 }
 
 // GetSettings returns the settings
 func GetSettings() *Settings {
 
+	s3_use_ssl, _ := strconv.ParseBool(os.Getenv("VIDEO_SVC_S3_USE_SSL"))
+	upload_expiry, _ := strconv.Atoi(os.Getenv("VIDEO_SVC_UPLOAD_EXPIRY"))
+	workers, _ := strconv.Atoi(os.Getenv("VIDEO_SVC_WORKERS"))
+	max_attempts, _ := strconv.Atoi(os.Getenv("VIDEO_SVC_JOB_MAX_ATTEMPTS"))
+	playback_ttl, _ := strconv.Atoi(os.Getenv("VIDEO_SVC_PLAYBACK_TTL"))
+
 	Settings := &Settings{
 		Server: &server{
 			GrpcHost: os.Getenv("VIDEO_SVC_GRPC_HOST"),
@@ -54,6 +111,59 @@ func GetSettings() *Settings {
 		Logger: &logger{
 			Level: os.Getenv("VIDEO_SVC_LOG_LEVEL"),
 		},
+
+		Storage: &storage{
+			Backend:   os.Getenv("VIDEO_SVC_STORAGE_BACKEND"),
+			LocalPath: os.Getenv("VIDEO_SVC_STORAGE_LOCAL_PATH"),
+			S3: &S3{
+				Endpoint:  os.Getenv("VIDEO_SVC_S3_ENDPOINT"),
+				AccessKey: os.Getenv("VIDEO_SVC_S3_ACCESS_KEY"),
+				SecretKey: os.Getenv("VIDEO_SVC_S3_SECRET_KEY"),
+				Bucket:    os.Getenv("VIDEO_SVC_S3_BUCKET"),
+				Region:    os.Getenv("VIDEO_SVC_S3_REGION"),
+				UseSSL:    s3_use_ssl,
+			},
+		},
+
+		Upload: &upload{
+			Expiry: upload_expiry,
+		},
+
+		Processing: &processing{
+			Transcoder:  os.Getenv("VIDEO_SVC_TRANSCODER"),
+			Prober:      os.Getenv("VIDEO_SVC_PROBER"),
+			FFmpegPath:  os.Getenv("VIDEO_SVC_FFMPEG_PATH"),
+			FFprobePath: os.Getenv("VIDEO_SVC_FFPROBE_PATH"),
+			Workers:     workers,
+			MaxAttempts: max_attempts,
+		},
+
+		Playback: &playback{
+			Secret: os.Getenv("VIDEO_SVC_PLAYBACK_SECRET"),
+			TTL:    playback_ttl,
+		},
+
+		PageToken: &pageToken{
+			Secret: os.Getenv("VIDEO_SVC_PAGE_TOKEN_SECRET"),
+		},
+
+		Channel: &channelService{
+			GrpcHost: os.Getenv("CHANNEL_SVC_GRPC_HOST"),
+			GrcpPort: os.Getenv("CHANNEL_SVC_GRPC_PORT"),
+		},
 	}
 	return Settings
 }
+
+// MissingSecrets returns the environment variables of the secrets left
+// empty, the service does not start without them
+func (s *Settings) MissingSecrets() []string {
+	var missing []string
+	if s.Playback.Secret == "" {
+		missing = append(missing, "VIDEO_SVC_PLAYBACK_SECRET")
+	}
+	if s.PageToken.Secret == "" {
+		missing = append(missing, "VIDEO_SVC_PAGE_TOKEN_SECRET")
+	}
+	return missing
+}
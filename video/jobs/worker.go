@@ -0,0 +1,201 @@
+// package jobs runs the background jobs of the video service
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/repo"
+)
+
+// Handler runs a job. progress reports the completion of the job between 0 and 1.
+type Handler func(ctx context.Context, job *model.Job, progress func(float64)) error
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails without further attempts
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Queue stores the jobs. The updates of a claimed job fail with
+// repo.ErrLeaseLost once another worker claimed it.
+type Queue interface {
+	ClaimJob(types []string, lease time.Duration) (*model.Job, error)
+	RenewJobLease(job *model.Job, lease time.Duration) error
+	UpdateJobProgress(job *model.Job, progress float64, lease time.Duration) error
+	FinishJob(job *model.Job) error
+	RetryJob(job *model.Job, cause string, runAt time.Time) error
+	FailJob(job *model.Job, cause string) error
+}
+
+// Worker polls the job table and runs the jobs with the registered handlers
+type Worker struct {
+	db           Queue
+	log          *log.Logger
+	handlers     map[string]Handler
+	concurrency  int
+	pollInterval time.Duration
+	lease        time.Duration
+	// renewInterval is the period at which the lease of a running job is
+	// extended, whatever the handler is doing
+	renewInterval time.Duration
+	baseBackoff   time.Duration
+	maxBackoff    time.Duration
+}
+
+// NewWorker returns a new worker running up to concurrency jobs at once
+func NewWorker(db Queue, logger *log.Logger, concurrency int) *Worker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Worker{
+		db:            db,
+		log:           logger,
+		handlers:      map[string]Handler{},
+		concurrency:   concurrency,
+		pollInterval:  5 * time.Second,
+		lease:         2 * time.Minute,
+		renewInterval: 30 * time.Second,
+		baseBackoff:   30 * time.Second,
+		maxBackoff:    30 * time.Minute,
+	}
+}
+
+// Handle registers the handler of a job type
+func (w *Worker) Handle(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run processes jobs until the context is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	types := make([]string, 0, len(w.handlers))
+	for jobType := range w.handlers {
+		types = append(types, jobType)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, types)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context, types []string) {
+	for {
+		job, err := w.db.ClaimJob(types, w.lease)
+		if err != nil {
+			if !errors.Is(err, repo.ErrNoJob) {
+				w.log.Printf("failed to claim job: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+		w.run(ctx, job)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// run executes a claimed job and records its outcome. The handler is
+// cancelled when the lease of the job is lost, its outcome is then dropped.
+func (w *Worker) run(ctx context.Context, job *model.Job) {
+	w.log.Printf("running %s job %s for video %s (attempt %d)", job.Type, job.Uuid, job.VideoID, job.Attempts)
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		w.renewLease(jobCtx, cancel, job)
+	}()
+	progress := func(p float64) {
+		err := w.db.UpdateJobProgress(job, p, w.lease)
+		if errors.Is(err, repo.ErrLeaseLost) {
+			cancel()
+		}
+		if err != nil {
+			w.log.Printf("failed to update progress of job %s: %v", job.Uuid, err)
+		}
+	}
+	err := w.handlers[job.Type](jobCtx, job, progress)
+	cancel()
+	<-renewed
+
+	var outcomeErr error
+	switch {
+	case err == nil:
+		outcomeErr = w.db.FinishJob(job)
+	case IsFinal(job, err):
+		w.log.Printf("%s job %s failed: %v", job.Type, job.Uuid, err)
+		outcomeErr = w.db.FailJob(job, err.Error())
+	default:
+		w.log.Printf("%s job %s failed: %v", job.Type, job.Uuid, err)
+		outcomeErr = w.db.RetryJob(job, err.Error(), time.Now().Add(w.backoff(job.Attempts)))
+	}
+	if errors.Is(outcomeErr, repo.ErrLeaseLost) {
+		w.log.Printf("job %s was claimed by another worker, its outcome is dropped", job.Uuid)
+	} else if outcomeErr != nil {
+		w.log.Printf("failed to record the outcome of job %s: %v", job.Uuid, outcomeErr)
+	}
+}
+
+// renewLease extends the lease of a job until ctx is done, cancelling the
+// job once the lease is lost
+func (w *Worker) renewLease(ctx context.Context, cancel context.CancelFunc, job *model.Job) {
+	ticker := time.NewTicker(w.renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := w.db.RenewJobLease(job, w.lease)
+		if errors.Is(err, repo.ErrLeaseLost) {
+			w.log.Printf("lost the lease of job %s", job.Uuid)
+			cancel()
+			return
+		}
+		if err != nil {
+			w.log.Printf("failed to renew the lease of job %s: %v", job.Uuid, err)
+		}
+	}
+}
+
+// backoff returns the exponential delay before the next attempt, with jitter
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := w.baseBackoff
+	for i := 1; i < attempt && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.maxBackoff {
+		delay = w.maxBackoff
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// IsFinal reports whether the failure of job with err ends the job for good
+func IsFinal(job *model.Job, err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts
+}
@@ -0,0 +1,233 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/repo"
+	"github.com/iamvasanth07/showcase/video/transcoder"
+)
+
+// memQueue is an in-memory Queue holding a single job
+type memQueue struct {
+	mu       sync.Mutex
+	job      model.Job
+	renewals int
+	// lost makes every update of the held job fail as if another worker
+	// claimed it
+	lost bool
+}
+
+func (q *memQueue) ClaimJob(types []string, lease time.Duration) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.job.State != model.JobStateQueued {
+		return nil, repo.ErrNoJob
+	}
+	q.job.State = model.JobStateRunning
+	q.job.Attempts++
+	q.job.LeaseUntil = time.Now().Add(lease)
+	job := q.job
+	return &job, nil
+}
+
+func (q *memQueue) held(job *model.Job) error {
+	if q.lost || q.job.State != model.JobStateRunning || q.job.Attempts != job.Attempts {
+		return repo.ErrLeaseLost
+	}
+	return nil
+}
+
+func (q *memQueue) RenewJobLease(job *model.Job, lease time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.held(job); err != nil {
+		return err
+	}
+	q.renewals++
+	q.job.LeaseUntil = time.Now().Add(lease)
+	return nil
+}
+
+func (q *memQueue) UpdateJobProgress(job *model.Job, progress float64, lease time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.held(job); err != nil {
+		return err
+	}
+	q.job.Progress = progress
+	q.job.LeaseUntil = time.Now().Add(lease)
+	return nil
+}
+
+func (q *memQueue) FinishJob(job *model.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.held(job); err != nil {
+		return err
+	}
+	q.job.State = model.JobStateDone
+	q.job.Progress = 1
+	return nil
+}
+
+func (q *memQueue) RetryJob(job *model.Job, cause string, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.held(job); err != nil {
+		return err
+	}
+	q.job.State = model.JobStateQueued
+	q.job.LastError = cause
+	q.job.RunAt = runAt
+	return nil
+}
+
+func (q *memQueue) FailJob(job *model.Job, cause string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.held(job); err != nil {
+		return err
+	}
+	q.job.State = model.JobStateFailed
+	q.job.LastError = cause
+	return nil
+}
+
+func (q *memQueue) snapshot() model.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.job
+}
+
+func newTestWorker(q *memQueue, handler Handler) *Worker {
+	w := NewWorker(q, log.New(io.Discard, "", 0), 1)
+	w.lease = time.Second
+	w.renewInterval = 10 * time.Millisecond
+	w.baseBackoff = time.Millisecond
+	w.maxBackoff = time.Millisecond
+	w.Handle(model.JobTypeTranscode, handler)
+	return w
+}
+
+func newQueue(maxAttempts int) *memQueue {
+	return &memQueue{job: model.Job{
+		Uuid:        "job",
+		VideoID:     "video",
+		Type:        model.JobTypeTranscode,
+		State:       model.JobStateQueued,
+		MaxAttempts: maxAttempts,
+	}}
+}
+
+// runOnce claims the job of the queue and runs it
+func runOnce(t *testing.T, w *Worker, q *memQueue) {
+	t.Helper()
+	job, err := q.ClaimJob([]string{model.JobTypeTranscode}, w.lease)
+	if err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+	w.run(context.Background(), job)
+}
+
+// transcodeHandler runs the fake transcoder on a placeholder source, like
+// the transcoding handler of the service
+func transcodeHandler(t *testing.T, fake *transcoder.FakeTranscoder) Handler {
+	return func(ctx context.Context, job *model.Job, progress func(float64)) error {
+		dir := t.TempDir()
+		input := filepath.Join(dir, "source.mp4")
+		if err := os.WriteFile(input, nil, 0o644); err != nil {
+			return err
+		}
+		_, err := fake.Transcode(ctx, input, filepath.Join(dir, "hls"), transcoder.DefaultLadder, progress)
+		return err
+	}
+}
+
+func TestWorkerFinishesTranscodeJob(t *testing.T) {
+	q := newQueue(3)
+	w := newTestWorker(q, transcodeHandler(t, transcoder.NewFakeTranscoder(10*time.Second)))
+	runOnce(t, w, q)
+	job := q.snapshot()
+	if job.State != model.JobStateDone || job.Progress != 1 {
+		t.Errorf("job = %s at %v, want done at 1", job.State, job.Progress)
+	}
+}
+
+func TestWorkerRetriesThenFails(t *testing.T) {
+	q := newQueue(2)
+	fake := transcoder.NewFakeTranscoder(10 * time.Second)
+	fake.Err = errors.New("ffmpeg crashed")
+	w := newTestWorker(q, transcodeHandler(t, fake))
+
+	runOnce(t, w, q)
+	if job := q.snapshot(); job.State != model.JobStateQueued || job.LastError != "ffmpeg crashed" {
+		t.Fatalf("after the first attempt job = %s (%q), want queued again", job.State, job.LastError)
+	}
+	runOnce(t, w, q)
+	if job := q.snapshot(); job.State != model.JobStateFailed || job.Attempts != 2 {
+		t.Errorf("after the last attempt job = %s after %d attempts, want failed after 2", job.State, job.Attempts)
+	}
+}
+
+func TestWorkerFailsPermanentErrors(t *testing.T) {
+	q := newQueue(3)
+	w := newTestWorker(q, func(ctx context.Context, job *model.Job, progress func(float64)) error {
+		return Permanent(errors.New("source missing"))
+	})
+	runOnce(t, w, q)
+	if job := q.snapshot(); job.State != model.JobStateFailed || job.Attempts != 1 {
+		t.Errorf("job = %s after %d attempts, want failed after 1", job.State, job.Attempts)
+	}
+}
+
+func TestWorkerRenewsLeaseWithoutProgress(t *testing.T) {
+	q := newQueue(3)
+	// a long download or upload reports no progress
+	w := newTestWorker(q, func(ctx context.Context, job *model.Job, progress func(float64)) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	runOnce(t, w, q)
+	job := q.snapshot()
+	if job.State != model.JobStateDone {
+		t.Errorf("job = %s, want done", job.State)
+	}
+	if q.renewals == 0 {
+		t.Error("the lease was never renewed while the handler ran")
+	}
+}
+
+func TestWorkerDropsOutcomeAfterLostLease(t *testing.T) {
+	q := newQueue(3)
+	cancelled := make(chan struct{})
+	w := newTestWorker(q, func(ctx context.Context, job *model.Job, progress func(float64)) error {
+		q.mu.Lock()
+		q.lost = true
+		q.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			close(cancelled)
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("the handler was not cancelled")
+		}
+	})
+	runOnce(t, w, q)
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("the handler was not cancelled after the lease was lost")
+	}
+	if job := q.snapshot(); job.State != model.JobStateRunning || job.LastError != "" {
+		t.Errorf("job = %s (%q), want the state of the new holder left untouched", job.State, job.LastError)
+	}
+}
@@ -0,0 +1,55 @@
+// gorm model for the background jobs of the video service
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// Job types
+const (
+	JobTypeTranscode  = "transcode"
+	JobTypeThumbnails = "thumbnails"
+	// JobTypeCleanup removes the stored files of a deleted video
+	JobTypeCleanup = "cleanup"
+	// JobTypeAssemble concatenates the parts of a finished resumable upload
+	// into the source file of its video
+	JobTypeAssemble = "assemble"
+)
+
+// Job states
+const (
+	JobStateQueued  = "queued"
+	JobStateRunning = "running"
+	JobStateFailed  = "failed"
+	JobStateDone    = "done"
+	// JobStateCancelled marks the jobs of a video deleted before they ended
+	JobStateCancelled = "cancelled"
+)
+
+type Job struct {
+	gorm.Model
+	Uuid        string `gorm:"primaryKey"`
+	VideoID     string `gorm:"index"`
+	Type        string `gorm:"not null"`
+	State       string `gorm:"not null;index"`
+	Attempts    int
+	MaxAttempts int
+	Progress    float64
+	LastError   string
+	RunAt       time.Time `gorm:"index"`
+	LeaseUntil  time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// Hook before create to generate uuid
+func (j *Job) BeforeCreate(tx *gorm.DB) error {
+	if j.Uuid == "" {
+		j.Uuid = uuid.NewV4().String()
+	}
+	return nil
+}
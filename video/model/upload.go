@@ -0,0 +1,50 @@
+// gorm model for the resumable uploads of the video service
+
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+type Upload struct {
+	gorm.Model
+	Uuid    string `gorm:"primaryKey"`
+	OwnerID string `gorm:"index"`
+	// ChannelID is the channel of the caller the video is published on
+	ChannelID   string
+	Length      int64 `gorm:"not null"`
+	Offset      int64 `gorm:"column:upload_offset;not null"`
+	FileName    string
+	ContentType string
+	Title       string
+	Description string
+	Category    string
+	Language    string
+	VideoID     string
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+// UploadPart is a stored chunk of an upload, starting at Offset
+type UploadPart struct {
+	gorm.Model
+	UploadID  string `gorm:"index"`
+	Offset    int64  `gorm:"column:part_offset"`
+	Size      int64
+	ObjectKey string
+}
+
+// Hook before create to generate uuid
+func (u *Upload) BeforeCreate(tx *gorm.DB) error {
+	if u.Uuid == "" {
+		u.Uuid = uuid.NewV4().String()
+	}
+	return nil
+}
+
+// Complete reports whether every byte of the upload was received
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Length
+}
@@ -3,6 +3,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -10,6 +11,20 @@ import (
 	"gorm.io/gorm"
 )
 
+// Processing states of a video
+const (
+	VideoStatusProcessing = "processing"
+	VideoStatusReady      = "ready"
+	VideoStatusFailed     = "failed"
+)
+
+// Privacy settings of a video
+const (
+	VideoPrivacyPublic   = "public"
+	VideoPrivacyUnlisted = "unlisted"
+	VideoPrivacyPrivate  = "private"
+)
+
 type Video struct {
 	gorm.Model
 	Uuid        string `gorm:"primaryKey"`
@@ -17,6 +32,7 @@ type Video struct {
 	Description string `gorm:"not null"`
 	Url         string `gorm:"not null"`
 	ChannelID   string
+	OwnerID     string `gorm:"index"`
 	Views       uint64
 	Duration    int32
 	Thumbnail   string
@@ -24,19 +40,109 @@ type Video struct {
 	Privacy     string
 	Category    string
 	Language    string
-	Tags        []string `gorm:"type:jsonb"`
+	Tags        []string `gorm:"type:jsonb;serializer:json;index:,type:gin"`
 	isDeleted   bool
 	Slug        string `gorm:"uniqueIndex"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   *time.Time
+	ObjectKey   string
+	ContentType string
+	Size        int64
+	Checksum    string
+	Status      string
+	PlaylistKey string
+	PreviewKey  string
+	// technical metadata read from the source file
+	Width      int32
+	Height     int32
+	FrameRate  float64
+	VideoCodec string
+	AudioCodec string
+	Bitrate    int64
+	Container  string
+	// SearchConfig is the text search configuration stemming the search
+	// vector, derived from the language. The column is created with the
+	// search vector, see migrateDB.
+	SearchConfig string `gorm:"-:migration"`
+	// CustomThumbnail is set once the owner uploaded a thumbnail, which the
+	// generated poster frame must not replace
+	CustomThumbnail bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
 }
 
-// Hook before create to generate uuid and slug
+// Hook before create to generate uuid and slug and apply the defaults
 func (u *Video) BeforeCreate(tx *gorm.DB) error {
-	uuid := uuid.NewV4()
-	u.Uuid = uuid.String()
+	if u.Uuid == "" {
+		u.Uuid = uuid.NewV4().String()
+	}
+	if u.PublishedAt.IsZero() {
+		u.PublishedAt = time.Now()
+	}
+	if u.Privacy == "" {
+		u.Privacy = VideoPrivacyPublic
+	}
 	u.Slug = slug.Make(u.Title)
-	u.Url = "https://www.showcase.com/watch?v=" + u.Slug
+	// titles are not unique, a taken slug is told apart by the start of the uuid
+	var taken int64
+	err := tx.Session(&gorm.Session{NewDB: true}).Model(&Video{}).
+		Where("slug = ?", u.Slug).Limit(1).Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 || u.Slug == "" {
+		u.Slug = strings.Trim(u.Slug+"-"+u.Uuid[:8], "-")
+	}
+	u.Url = "/api/v1/videos/" + u.Slug + "/playback"
 	return nil
 }
+
+// Hook before save to derive the text search configuration from the language
+func (u *Video) BeforeSave(tx *gorm.DB) error {
+	u.SearchConfig = SearchConfig(u.Language)
+	return nil
+}
+
+// searchConfigs maps languages to the text search configurations shipped
+// with Postgres
+var searchConfigs = map[string]string{
+	"ar": "arabic",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hi": "hindi",
+	"hu": "hungarian",
+	"id": "indonesian",
+	"it": "italian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"ta": "tamil",
+	"tr": "turkish",
+}
+
+// SearchConfig returns the text search configuration of a language, given
+// as an ISO 639-1 code (optionally with a region) or an english name.
+// Unknown languages are not stemmed.
+func SearchConfig(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	language = strings.ReplaceAll(language, "_", "-")
+	if code, _, found := strings.Cut(language, "-"); found {
+		language = code
+	}
+	if config, ok := searchConfigs[language]; ok {
+		return config
+	}
+	for _, config := range searchConfigs {
+		if config == language {
+			return config
+		}
+	}
+	return "simple"
+}
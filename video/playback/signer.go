@@ -0,0 +1,89 @@
+// package playback signs and verifies the short lived playback urls of videos
+
+package playback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathPrefix is the gateway route serving signed playback urls
+const PathPrefix = "/api/v1/play"
+
+// defaultTTL is used when no ttl is configured
+const defaultTTL = 15 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned for tampered or foreign urls
+	ErrInvalidSignature = errors.New("invalid playback signature")
+	// ErrExpired is returned for urls past their expiry
+	ErrExpired = errors.New("playback url expired")
+	// ErrInvalidPath is returned for files outside of the playable objects of a video
+	ErrInvalidPath = errors.New("invalid playback path")
+)
+
+// Signer mints and verifies playback urls with an HMAC-SHA256 secret. The
+// signature covers the video and the expiry, so the relative segment urls of
+// an HLS playlist resolve below the same signed prefix.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner returns a new signer issuing urls valid for ttl
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Signer{secret: []byte(secret), ttl: ttl}
+}
+
+// URL returns the signed url of file, relative to the objects of the video,
+// together with its expiry
+func (s *Signer) URL(videoID string, file string, now time.Time) (string, time.Time) {
+	expires := now.Add(s.ttl).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return fmt.Sprintf("%s/%s/%s/%s/%s", PathPrefix, videoID, exp, s.sign(videoID, exp), file), expires
+}
+
+// Verify checks the signature and the expiry of a playback url
+func (s *Signer) Verify(videoID string, exp string, sig string, now time.Time) error {
+	expected := s.sign(videoID, exp)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *Signer) sign(videoID string, exp string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(videoID + "\n" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ObjectKey maps a file of a playback url to its storage key. Only the HLS
+// output, the thumbnails and the original upload of the video are served.
+func ObjectKey(videoID string, file string) (string, error) {
+	clean := path.Clean("/" + file)[1:]
+	if clean == "" || videoID == "" || videoID == "." || videoID == ".." || strings.Contains(videoID, "/") {
+		return "", ErrInvalidPath
+	}
+	if !strings.HasPrefix(clean, "hls/") && !strings.HasPrefix(clean, "thumbnails/") && !strings.HasPrefix(clean, "source") {
+		return "", ErrInvalidPath
+	}
+	return path.Join("videos", videoID, clean), nil
+}
@@ -0,0 +1,82 @@
+package playback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// splitURL returns the video id, the expiry, the signature and the file of a playback url
+func splitURL(t *testing.T, url string) (string, string, string, string) {
+	t.Helper()
+	parts := strings.SplitN(strings.TrimPrefix(url, PathPrefix+"/"), "/", 4)
+	if len(parts) != 4 {
+		t.Fatalf("malformed playback url %q", url)
+	}
+	return parts[0], parts[1], parts[2], parts[3]
+}
+
+func TestSignerVerify(t *testing.T) {
+	signer := NewSigner("secret", time.Minute)
+	now := time.Now()
+	url, expires := signer.URL("video-1", "hls/master.m3u8", now)
+	id, exp, sig, file := splitURL(t, url)
+	if id != "video-1" || file != "hls/master.m3u8" {
+		t.Fatalf("url %q does not carry the video and the file", url)
+	}
+	if want := now.Add(time.Minute).Truncate(time.Second); !expires.Equal(want) {
+		t.Errorf("expires = %v, want %v", expires, want)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		exp  string
+		sig  string
+		now  time.Time
+		want error
+	}{
+		{"valid", id, exp, sig, now, nil},
+		{"at expiry", id, exp, sig, expires, nil},
+		{"expired", id, exp, sig, expires.Add(time.Second), ErrExpired},
+		{"other video", "video-2", exp, sig, now, ErrInvalidSignature},
+		{"extended expiry", id, "9999999999", sig, now, ErrInvalidSignature},
+		{"tampered signature", id, exp, sig[:len(sig)-1] + "A", now, ErrInvalidSignature},
+		{"empty signature", id, exp, "", now, ErrInvalidSignature},
+		{"foreign secret", id, exp, NewSigner("other", time.Minute).sign(id, exp), now, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := signer.Verify(tt.id, tt.exp, tt.sig, tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	tests := []struct {
+		videoID string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"v1", "/hls/720p/segment0.ts", "videos/v1/hls/720p/segment0.ts", false},
+		{"v1", "/thumbnails/poster.jpg", "videos/v1/thumbnails/poster.jpg", false},
+		{"v1", "/source.mp4", "videos/v1/source.mp4", false},
+		{"v1", "/hls/../../v2/source.mp4", "", true},
+		{"v1", "/../v2/hls/master.m3u8", "", true},
+		{"v1", "/uploads/part", "", true},
+		{"v1", "/", "", true},
+		{"..", "/hls/master.m3u8", "", true},
+		{"a/b", "/hls/master.m3u8", "", true},
+		{"", "/hls/master.m3u8", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ObjectKey(tt.videoID, tt.file)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ObjectKey(%q, %q) = %q, %v, want %q", tt.videoID, tt.file, got, err, tt.want)
+		}
+	}
+}
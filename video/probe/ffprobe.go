@@ -0,0 +1,101 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FFprobe reads the metadata with the ffprobe executable
+type FFprobe struct {
+	path string
+}
+
+// NewFFprobe returns a new ffprobe prober. An empty path is looked up in $PATH.
+func NewFFprobe(path string) *FFprobe {
+	if path == "" {
+		path = "ffprobe"
+	}
+	return &FFprobe{path: path}
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+func (f *FFprobe) Probe(ctx context.Context, path string) (*Metadata, error) {
+	cmd := exec.CommandContext(ctx, f.path,
+		"-v", "error",
+		"-show_entries", "stream=codec_type,codec_name,width,height,avg_frame_rate:format=format_name,duration,bit_rate",
+		"-of", "json",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			// ffprobe fails on files it cannot demux
+			return nil, fmt.Errorf("%w: %s", ErrUnsupported, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	meta := &Metadata{Container: probe.Format.FormatName}
+	seconds, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	meta.Duration = time.Duration(seconds * float64(time.Second))
+	meta.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	for _, s := range probe.Streams {
+		switch s.CodecType {
+		case "video":
+			if meta.VideoCodec != "" {
+				continue
+			}
+			meta.VideoCodec = s.CodecName
+			meta.Width = s.Width
+			meta.Height = s.Height
+			meta.FrameRate = parseRate(s.AvgFrameRate)
+		case "audio":
+			if meta.AudioCodec == "" {
+				meta.AudioCodec = s.CodecName
+			}
+		}
+	}
+	if err := validate(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// parseRate parses the "num/den" frame rates of ffprobe
+func parseRate(rate string) float64 {
+	num, den, found := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
@@ -0,0 +1,322 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// MP4Prober reads the metadata of ISO base media files (mp4, mov) in pure Go.
+// It is used where ffprobe is not installed.
+type MP4Prober struct{}
+
+// NewMP4Prober returns a new mp4 prober
+func NewMP4Prober() *MP4Prober {
+	return &MP4Prober{}
+}
+
+// maxBoxPayload limits the boxes read in memory
+const maxBoxPayload = 64 << 20
+
+var errMalformed = errors.New("malformed mp4")
+
+type box struct {
+	typ    string
+	offset int64 // start of the payload
+	size   int64 // size of the payload
+}
+
+// mp4Track is the metadata of a single trak box
+type mp4Track struct {
+	handler     string
+	codec       string
+	width       int
+	height      int
+	timescale   uint32
+	duration    uint64
+	sampleCount uint64
+}
+
+func (p *MP4Prober) Probe(ctx context.Context, path string) (*Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := parseMP4(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func parseMP4(r io.ReaderAt, size int64) (*Metadata, error) {
+	top, err := readBoxes(r, 0, size)
+	if err != nil || len(top) == 0 || top[0].typ != "ftyp" {
+		return nil, fmt.Errorf("%w: not an mp4 file", ErrUnsupported)
+	}
+	ftyp, err := readPayload(r, top[0])
+	if err != nil || len(ftyp) < 4 {
+		return nil, errMalformed
+	}
+	meta := &Metadata{Container: "mp4"}
+	if string(ftyp[:4]) == "qt  " {
+		meta.Container = "mov"
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing moov box", errMalformed)
+	}
+	children, err := readBoxes(r, moov.offset, moov.size)
+	if err != nil {
+		return nil, err
+	}
+	if mvhd, ok := findBox(children, "mvhd"); ok {
+		data, err := readPayload(r, mvhd)
+		if err != nil {
+			return nil, err
+		}
+		timescale, duration, err := parseTimes(data, 12, 20)
+		if err != nil {
+			return nil, err
+		}
+		if timescale > 0 {
+			meta.Duration = time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+		}
+	}
+	for _, b := range children {
+		if b.typ != "trak" {
+			continue
+		}
+		track, err := parseTrack(r, b)
+		if err != nil {
+			return nil, err
+		}
+		switch track.handler {
+		case "vide":
+			if meta.VideoCodec != "" {
+				continue
+			}
+			meta.VideoCodec = track.codec
+			meta.Width = track.width
+			meta.Height = track.height
+			if track.duration > 0 && track.timescale > 0 {
+				meta.FrameRate = float64(track.sampleCount) * float64(track.timescale) / float64(track.duration)
+			}
+		case "soun":
+			if meta.AudioCodec == "" {
+				meta.AudioCodec = track.codec
+			}
+		}
+	}
+	if meta.Duration > 0 {
+		meta.Bitrate = int64(float64(size*8) / meta.Duration.Seconds())
+	}
+	return meta, nil
+}
+
+func parseTrack(r io.ReaderAt, trak box) (*mp4Track, error) {
+	track := &mp4Track{}
+	children, err := readBoxes(r, trak.offset, trak.size)
+	if err != nil {
+		return nil, err
+	}
+	if tkhd, ok := findBox(children, "tkhd"); ok {
+		data, err := readPayload(r, tkhd)
+		if err != nil {
+			return nil, err
+		}
+		// width and height are the last two 16.16 fixed point fields
+		if len(data) < 8 {
+			return nil, errMalformed
+		}
+		track.width = int(binary.BigEndian.Uint32(data[len(data)-8:]) >> 16)
+		track.height = int(binary.BigEndian.Uint32(data[len(data)-4:]) >> 16)
+	}
+	mdia, ok := findBox(children, "mdia")
+	if !ok {
+		return track, nil
+	}
+	if children, err = readBoxes(r, mdia.offset, mdia.size); err != nil {
+		return nil, err
+	}
+	if hdlr, ok := findBox(children, "hdlr"); ok {
+		data, err := readPayload(r, hdlr)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 12 {
+			return nil, errMalformed
+		}
+		track.handler = string(data[8:12])
+	}
+	if mdhd, ok := findBox(children, "mdhd"); ok {
+		data, err := readPayload(r, mdhd)
+		if err != nil {
+			return nil, err
+		}
+		if track.timescale, track.duration, err = parseTimes(data, 12, 20); err != nil {
+			return nil, err
+		}
+	}
+	stbl, err := findPath(r, children, "minf", "stbl")
+	if err != nil || stbl == nil {
+		return track, err
+	}
+	if children, err = readBoxes(r, stbl.offset, stbl.size); err != nil {
+		return nil, err
+	}
+	if stsd, ok := findBox(children, "stsd"); ok {
+		data, err := readPayload(r, stsd)
+		if err != nil {
+			return nil, err
+		}
+		// version/flags, entry count, then the first sample entry header
+		if len(data) < 16 {
+			return nil, errMalformed
+		}
+		track.codec = codecName(string(data[12:16]))
+	}
+	if stts, ok := findBox(children, "stts"); ok {
+		data, err := readPayload(r, stts)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 8 {
+			return nil, errMalformed
+		}
+		entries := int(binary.BigEndian.Uint32(data[4:8]))
+		for i := 0; i < entries && 8+i*8+8 <= len(data); i++ {
+			track.sampleCount += uint64(binary.BigEndian.Uint32(data[8+i*8:]))
+		}
+	}
+	return track, nil
+}
+
+// parseTimes reads the timescale and duration of mvhd and mdhd boxes, whose
+// layout depends on the box version. off32 and off64 are the offsets of the
+// timescale for versions 0 and 1.
+func parseTimes(data []byte, off32, off64 int) (uint32, uint64, error) {
+	if len(data) < 1 {
+		return 0, 0, errMalformed
+	}
+	if data[0] == 1 {
+		if len(data) < off64+12 {
+			return 0, 0, errMalformed
+		}
+		return binary.BigEndian.Uint32(data[off64:]), binary.BigEndian.Uint64(data[off64+4:]), nil
+	}
+	if len(data) < off32+8 {
+		return 0, 0, errMalformed
+	}
+	return binary.BigEndian.Uint32(data[off32:]), uint64(binary.BigEndian.Uint32(data[off32+4:])), nil
+}
+
+// readBoxes lists the boxes in the given range of r
+func readBoxes(r io.ReaderAt, offset, size int64) ([]box, error) {
+	var (
+		boxes  []box
+		header = make([]byte, 16)
+		end    = offset + size
+	)
+	for offset+8 <= end {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return nil, errMalformed
+		}
+		length := int64(binary.BigEndian.Uint32(header))
+		b := box{typ: string(header[4:8]), offset: offset + 8}
+		switch length {
+		case 0: // the box extends to the end
+			length = end - offset
+		case 1: // 64 bit size follows the type
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, errMalformed
+			}
+			length = int64(binary.BigEndian.Uint64(header[8:16]))
+			b.offset += 8
+		}
+		if length < b.offset-offset || length > end-offset {
+			return nil, errMalformed
+		}
+		b.size = offset + length - b.offset
+		boxes = append(boxes, b)
+		offset += length
+	}
+	return boxes, nil
+}
+
+func readPayload(r io.ReaderAt, b box) ([]byte, error) {
+	if b.size > maxBoxPayload {
+		return nil, fmt.Errorf("%w: %s box too large", errMalformed, b.typ)
+	}
+	data := make([]byte, b.size)
+	if _, err := r.ReadAt(data, b.offset); err != nil {
+		return nil, errMalformed
+	}
+	return data, nil
+}
+
+func findBox(boxes []box, typ string) (box, bool) {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b, true
+		}
+	}
+	return box{}, false
+}
+
+// findPath descends through the nested boxes named in path
+func findPath(r io.ReaderAt, boxes []box, path ...string) (*box, error) {
+	for i, typ := range path {
+		b, ok := findBox(boxes, typ)
+		if !ok {
+			return nil, nil
+		}
+		if i == len(path)-1 {
+			return &b, nil
+		}
+		var err error
+		if boxes, err = readBoxes(r, b.offset, b.size); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// codecName maps sample entry types to the codec names of ffprobe
+func codecName(fourcc string) string {
+	switch fourcc {
+	case "avc1", "avc3":
+		return "h264"
+	case "hvc1", "hev1":
+		return "hevc"
+	case "av01":
+		return "av1"
+	case "vp09":
+		return "vp9"
+	case "mp4v":
+		return "mpeg4"
+	case "mp4a":
+		return "aac"
+	case "Opus":
+		return "opus"
+	case "ac-3":
+		return "ac3"
+	case "ec-3":
+		return "eac3"
+	case ".mp3":
+		return "mp3"
+	}
+	return fourcc
+}
@@ -0,0 +1,274 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mp4Box returns a box of the type holding the concatenated payload
+func mp4Box(typ string, payload ...[]byte) []byte {
+	data := bytes.Join(payload, nil)
+	return append(header(uint32(8+len(data)), typ), data...)
+}
+
+func header(size uint32, typ string) []byte {
+	return append(u32(size), typ...)
+}
+
+// largeBox returns a box whose size is stored in the 64 bit field
+func largeBox(typ string, payload []byte) []byte {
+	return append(append(header(1, typ), u64(uint64(16+len(payload)))...), payload...)
+}
+
+func u32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
+
+func u64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func ftyp(brand string) []byte {
+	return mp4Box("ftyp", []byte(brand), u32(0x200), []byte("isomavc1"))
+}
+
+// mvhd returns a version 0 movie header
+func mvhd(timescale, duration uint32) []byte {
+	return mp4Box("mvhd", u32(0), u32(0), u32(0), u32(timescale), u32(duration), make([]byte, 80))
+}
+
+// mvhdV1 returns a version 1 movie header, with 64 bit times
+func mvhdV1(timescale uint32, duration uint64) []byte {
+	return mp4Box("mvhd", u32(1<<24), u64(0), u64(0), u32(timescale), u64(duration), make([]byte, 80))
+}
+
+func tkhd(width, height uint32) []byte {
+	return mp4Box("tkhd", u32(0), make([]byte, 72), u32(width<<16), u32(height<<16))
+}
+
+func mdhd(timescale, duration uint32) []byte {
+	return mp4Box("mdhd", u32(0), u32(0), u32(0), u32(timescale), u32(duration), u32(0))
+}
+
+func hdlr(handler string) []byte {
+	return mp4Box("hdlr", u32(0), u32(0), []byte(handler), make([]byte, 12), []byte("handler\x00"))
+}
+
+func stsd(fourcc string) []byte {
+	return mp4Box("stsd", u32(0), u32(1), u32(16), []byte(fourcc), make([]byte, 8))
+}
+
+// stts returns a time to sample table of count samples lasting delta each
+func stts(count, delta uint32) []byte {
+	return mp4Box("stts", u32(0), u32(1), u32(count), u32(delta))
+}
+
+func trak(tkhd []byte, handler, fourcc string, timescale, samples, delta uint32) []byte {
+	return mp4Box("trak", tkhd, mp4Box("mdia",
+		mdhd(timescale, samples*delta),
+		hdlr(handler),
+		mp4Box("minf", mp4Box("stbl", stsd(fourcc), stts(samples, delta))),
+	))
+}
+
+// videoTrak is a 2s 1280x720 h264 track at 30 frames per second
+func videoTrak() []byte {
+	return trak(tkhd(1280, 720), "vide", "avc1", 15360, 60, 512)
+}
+
+// audioTrak is a 2s aac track
+func audioTrak() []byte {
+	return trak(tkhd(0, 0), "soun", "mp4a", 48000, 94, 1024)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestParseMP4(t *testing.T) {
+	moov := mp4Box("moov", mvhd(1000, 2000), videoTrak(), audioTrak())
+	tests := []struct {
+		name string
+		file []byte
+		want Metadata
+	}{
+		{
+			name: "mp4",
+			file: join(ftyp("isom"), moov),
+			want: Metadata{Container: "mp4", Duration: 2 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264", AudioCodec: "aac"},
+		},
+		{
+			name: "quicktime brand",
+			file: join(ftyp("qt  "), moov),
+			want: Metadata{Container: "mov", Duration: 2 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264", AudioCodec: "aac"},
+		},
+		{
+			name: "moov after a 64 bit sized mdat",
+			file: join(ftyp("isom"), largeBox("mdat", make([]byte, 32)), moov),
+			want: Metadata{Container: "mp4", Duration: 2 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264", AudioCodec: "aac"},
+		},
+		{
+			name: "last box sized to the end of the file",
+			file: join(ftyp("isom"), header(0, "moov"), mvhd(1000, 2000), videoTrak()),
+			want: Metadata{Container: "mp4", Duration: 2 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264"},
+		},
+		{
+			name: "version 1 movie header",
+			file: join(ftyp("isom"), mp4Box("moov", mvhdV1(90000, 270000), videoTrak())),
+			want: Metadata{Container: "mp4", Duration: 3 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264"},
+		},
+		{
+			name: "first video track wins",
+			file: join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000), videoTrak(),
+				trak(tkhd(640, 360), "vide", "hvc1", 15360, 30, 512))),
+			want: Metadata{Container: "mp4", Duration: 2 * time.Second, Width: 1280, Height: 720, FrameRate: 30, VideoCodec: "h264"},
+		},
+		{
+			name: "unknown codec keeps its fourcc",
+			file: join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000), trak(tkhd(320, 240), "vide", "xvid", 25, 50, 1))),
+			want: Metadata{Container: "mp4", Duration: 2 * time.Second, Width: 320, Height: 240, FrameRate: 25, VideoCodec: "xvid"},
+		},
+		{
+			name: "track without media box",
+			file: join(ftyp("isom"), mp4Box("moov", mvhd(1000, 0), mp4Box("trak", tkhd(1280, 720)))),
+			want: Metadata{Container: "mp4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := parseMP4(bytes.NewReader(tt.file), int64(len(tt.file)))
+			if err != nil {
+				t.Fatalf("parseMP4() error = %v", err)
+			}
+			tt.want.Bitrate = meta.Bitrate
+			if tt.want.Duration > 0 {
+				tt.want.Bitrate = int64(float64(len(tt.file)*8) / tt.want.Duration.Seconds())
+			}
+			if *meta != tt.want {
+				t.Errorf("parseMP4() = %+v, want %+v", *meta, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMP4Rejects(t *testing.T) {
+	valid := join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000), videoTrak()))
+	tests := []struct {
+		name string
+		file []byte
+		want error
+	}{
+		{name: "empty file", file: nil, want: ErrUnsupported},
+		{name: "not an mp4 file", file: []byte("RIFF\x00\x00\x00\x00AVI LIST"), want: ErrUnsupported},
+		{name: "ftyp not first", file: join(mp4Box("moov", mvhd(1000, 2000)), ftyp("isom")), want: ErrUnsupported},
+		{name: "ftyp without brand", file: join(mp4Box("ftyp"), mp4Box("moov")), want: errMalformed},
+		{name: "missing moov", file: join(ftyp("isom"), mp4Box("mdat", make([]byte, 16))), want: errMalformed},
+		{name: "truncated file", file: valid[:len(valid)-20], want: ErrUnsupported},
+		{name: "box smaller than its header", file: join(ftyp("isom"), header(4, "moov"), make([]byte, 8)), want: ErrUnsupported},
+		{name: "truncated 64 bit size", file: join(ftyp("isom"), header(1, "mdat")), want: ErrUnsupported},
+		{name: "negative 64 bit size", file: join(ftyp("isom"), header(1, "mdat"), u64(1<<63)), want: ErrUnsupported},
+		{name: "overflowing 64 bit size", file: join(ftyp("isom"), header(1, "mdat"), u64(1<<63-1)), want: ErrUnsupported},
+		{
+			name: "child larger than its parent",
+			file: join(ftyp("isom"), mp4Box("moov", header(64, "mvhd"), make([]byte, 8)), make([]byte, 64)),
+			want: errMalformed,
+		},
+		{
+			name: "nested overflowing 64 bit size",
+			file: join(ftyp("isom"), mp4Box("moov", header(1, "trak"), u64(1<<63-1))),
+			want: errMalformed,
+		},
+		{name: "short movie header", file: join(ftyp("isom"), mp4Box("moov", mp4Box("mvhd", u32(0), u32(0)))), want: errMalformed},
+		{name: "short version 1 movie header", file: join(ftyp("isom"), mp4Box("moov", mp4Box("mvhd", u32(1<<24), make([]byte, 20)))), want: errMalformed},
+		{name: "empty movie header", file: join(ftyp("isom"), mp4Box("moov", mp4Box("mvhd"))), want: errMalformed},
+		{name: "short track header", file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("tkhd", u32(0))))), want: errMalformed},
+		{
+			name: "short handler",
+			file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("mdia", mp4Box("hdlr", u32(0), []byte("vide")))))),
+			want: errMalformed,
+		},
+		{
+			name: "short media header",
+			file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("mdia", mp4Box("mdhd", u32(0)))))),
+			want: errMalformed,
+		},
+		{
+			name: "short sample description",
+			file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("mdia",
+				mp4Box("minf", mp4Box("stbl", mp4Box("stsd", u32(0), u32(1)))))))),
+			want: errMalformed,
+		},
+		{
+			name: "short time to sample table",
+			file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("mdia",
+				mp4Box("minf", mp4Box("stbl", mp4Box("stts", u32(0)))))))),
+			want: errMalformed,
+		},
+		{
+			name: "malformed sample table",
+			file: join(ftyp("isom"), mp4Box("moov", mp4Box("trak", mp4Box("mdia",
+				mp4Box("minf", mp4Box("stbl", header(64, "stsd"))))))),
+			want: errMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMP4(bytes.NewReader(tt.file), int64(len(tt.file)))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("parseMP4() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMP4TimeToSampleTableCount(t *testing.T) {
+	// the entry count claims more entries than the box holds
+	table := mp4Box("stts", u32(0), u32(1000), u32(60), u32(512))
+	file := join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000), mp4Box("trak", tkhd(1280, 720), mp4Box("mdia",
+		mdhd(15360, 30720), hdlr("vide"), mp4Box("minf", mp4Box("stbl", stsd("avc1"), table))))))
+	meta, err := parseMP4(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("parseMP4() error = %v", err)
+	}
+	if meta.FrameRate != 30 {
+		t.Errorf("FrameRate = %v, want 30 from the entries present", meta.FrameRate)
+	}
+}
+
+func TestReadPayloadTooLarge(t *testing.T) {
+	_, err := readPayload(bytes.NewReader(nil), box{typ: "mdat", size: maxBoxPayload + 1})
+	if !errors.Is(err, errMalformed) {
+		t.Errorf("readPayload() error = %v, want %v", err, errMalformed)
+	}
+}
+
+func TestMP4ProberValidates(t *testing.T) {
+	tests := []struct {
+		name string
+		file []byte
+		want error
+	}{
+		{name: "video", file: join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000), videoTrak(), audioTrak()))},
+		{name: "audio only", file: join(ftyp("M4A "), mp4Box("moov", mvhd(1000, 2000), audioTrak())), want: ErrNoVideo},
+		{name: "video track without size", file: join(ftyp("isom"), mp4Box("moov", mvhd(1000, 2000),
+			trak(tkhd(0, 0), "vide", "avc1", 15360, 60, 512))), want: ErrNoVideo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "source.mp4")
+			if err := os.WriteFile(path, tt.file, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := NewMP4Prober().Probe(context.Background(), path)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Probe() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// package probe reads the technical metadata of uploaded media files
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/config"
+)
+
+var (
+	// ErrUnsupported is returned for containers the platform does not accept
+	ErrUnsupported = errors.New("unsupported container")
+	// ErrNoVideo is returned for files without a video stream
+	ErrNoVideo = errors.New("no video stream")
+)
+
+// Metadata is the technical metadata of a media file
+type Metadata struct {
+	Container  string
+	Duration   time.Duration
+	Width      int
+	Height     int
+	FrameRate  float64
+	VideoCodec string
+	AudioCodec string
+	Bitrate    int64 // bit/s
+}
+
+// Prober reads the metadata of the media file at path
+type Prober interface {
+	Probe(ctx context.Context, path string) (*Metadata, error)
+}
+
+// supportedContainers are the accepted containers, as named by ffprobe
+var supportedContainers = []string{"mp4", "mov", "matroska", "webm", "avi", "mpegts", "flv"}
+
+// validate rejects unsupported containers and files without a video stream
+func validate(meta *Metadata) error {
+	supported := false
+	for _, name := range strings.Split(meta.Container, ",") {
+		for _, c := range supportedContainers {
+			if name == c {
+				supported = true
+			}
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%w: %s", ErrUnsupported, meta.Container)
+	}
+	if meta.VideoCodec == "" || meta.Width == 0 || meta.Height == 0 {
+		return ErrNoVideo
+	}
+	return nil
+}
+
+// fallback uses the secondary prober when the primary one is not installed
+type fallback struct {
+	primary   Prober
+	secondary Prober
+}
+
+func (f *fallback) Probe(ctx context.Context, path string) (*Metadata, error) {
+	meta, err := f.primary.Probe(ctx, path)
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return f.secondary.Probe(ctx, path)
+	}
+	return meta, err
+}
+
+// New returns the prober selected in the settings. ffprobe falls back to the
+// pure Go mp4 parser when the executable is missing.
+func New(settings *config.Settings) (Prober, error) {
+	switch settings.Processing.Prober {
+	case "", "ffprobe":
+		return &fallback{
+			primary:   NewFFprobe(settings.Processing.FFprobePath),
+			secondary: NewMP4Prober(),
+		}, nil
+	case "mp4":
+		return NewMP4Prober(), nil
+	}
+	return nil, fmt.Errorf("unknown prober %q", settings.Processing.Prober)
+}
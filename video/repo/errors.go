@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// unique_violation, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const uniqueViolation = "23505"
+
+// DuplicateError is returned when a unique column already holds the value
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s already exists", e.Field)
+}
+
+// translate converts postgres errors into repo errors
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return &DuplicateError{Field: fieldFromConstraint(pgErr.ConstraintName)}
+	}
+	return err
+}
+
+// fieldFromConstraint extracts the column from gorm's idx_<table>_<column> index names
+func fieldFromConstraint(constraint string) string {
+	for _, field := range []string{"slug"} {
+		if strings.HasSuffix(constraint, "_"+field) {
+			return field
+		}
+	}
+	return constraint
+}
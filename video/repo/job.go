@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoJob is returned when no job is ready to run
+var ErrNoJob = errors.New("no job ready")
+
+// ErrLeaseLost is returned when a worker updates a job it no longer holds,
+// after its lease expired and another worker claimed the job
+var ErrLeaseLost = errors.New("job lease lost")
+
+// CreateUploadedVideo creates a video whose source is stored along with its
+// processing jobs
+func (v *VideoRepo) CreateUploadedVideo(video *model.Video, jobs []*model.Job) error {
+	return translate(v.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(video).Error; err != nil {
+			return err
+		}
+		return tx.Create(jobs).Error
+	}))
+}
+
+// ClaimJob locks the next job that is due, or whose worker lost its lease
+// before its last attempt, and marks it as running until lease. The jobs
+// whose last attempt lost its lease, such as the ones crashing their worker,
+// are failed.
+func (v *VideoRepo) ClaimJob(types []string, lease time.Duration) (*model.Job, error) {
+	var job model.Job
+	err := v.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := failAbandonedJobs(tx, types, now); err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("type IN ?", types).
+			Where("(state = ? AND run_at <= ?) OR (state = ? AND lease_until < ? AND attempts < max_attempts)",
+				model.JobStateQueued, now, model.JobStateRunning, now).
+			Order("run_at asc").
+			First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoJob
+		}
+		if err != nil {
+			return err
+		}
+		job.State = model.JobStateRunning
+		job.Attempts++
+		job.Progress = 0
+		job.LeaseUntil = now.Add(lease)
+		job.StartedAt = &now
+		return tx.Save(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// failAbandonedJobs fails the running jobs whose lease expired on their last
+// attempt, along with the videos still processing they were producing
+func failAbandonedJobs(tx *gorm.DB, types []string, now time.Time) error {
+	var abandoned []model.Job
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("type IN ? AND state = ? AND lease_until < ? AND attempts >= max_attempts",
+			types, model.JobStateRunning, now).
+		Find(&abandoned).Error
+	if err != nil || len(abandoned) == 0 {
+		return err
+	}
+	ids := make([]string, 0, len(abandoned))
+	var videoIDs []string
+	for _, job := range abandoned {
+		ids = append(ids, job.Uuid)
+		if job.Type == model.JobTypeTranscode || job.Type == model.JobTypeAssemble {
+			videoIDs = append(videoIDs, job.VideoID)
+		}
+	}
+	err = tx.Model(&model.Job{}).Where("uuid IN ?", ids).Updates(map[string]interface{}{
+		"state":       model.JobStateFailed,
+		"last_error":  "lease expired on the last attempt",
+		"finished_at": &now,
+	}).Error
+	if err != nil || len(videoIDs) == 0 {
+		return err
+	}
+	return tx.Model(&model.Video{}).
+		Where("uuid IN ? AND status = ?", videoIDs, model.VideoStatusProcessing).
+		Update("status", model.VideoStatusFailed).Error
+}
+
+// RenewJobLease extends the lease of a running job, failing with
+// ErrLeaseLost once another worker claimed it
+func (v *VideoRepo) RenewJobLease(job *model.Job, lease time.Duration) error {
+	return leaseResult(v.heldJob(job).Update("lease_until", time.Now().Add(lease)))
+}
+
+// UpdateJobProgress records the progress of a running job and extends its lease
+func (v *VideoRepo) UpdateJobProgress(job *model.Job, progress float64, lease time.Duration) error {
+	return leaseResult(v.heldJob(job).Updates(map[string]interface{}{
+		"progress":    progress,
+		"lease_until": time.Now().Add(lease),
+	}))
+}
+
+// FinishJob marks a job as done
+func (v *VideoRepo) FinishJob(job *model.Job) error {
+	now := time.Now()
+	return leaseResult(v.heldJob(job).Updates(map[string]interface{}{
+		"state":       model.JobStateDone,
+		"progress":    1,
+		"last_error":  "",
+		"finished_at": &now,
+	}))
+}
+
+// RetryJob queues a failed job again at runAt
+func (v *VideoRepo) RetryJob(job *model.Job, cause string, runAt time.Time) error {
+	return leaseResult(v.heldJob(job).Updates(map[string]interface{}{
+		"state":      model.JobStateQueued,
+		"last_error": cause,
+		"run_at":     runAt,
+	}))
+}
+
+// FailJob marks a job as failed for good
+func (v *VideoRepo) FailJob(job *model.Job, cause string) error {
+	now := time.Now()
+	return leaseResult(v.heldJob(job).Updates(map[string]interface{}{
+		"state":       model.JobStateFailed,
+		"last_error":  cause,
+		"finished_at": &now,
+	}))
+}
+
+// heldJob selects a job as long as it runs the attempt it was claimed for,
+// the attempts count tells the claims of the job apart
+func (v *VideoRepo) heldJob(job *model.Job) *gorm.DB {
+	return v.db.Model(&model.Job{}).
+		Where("uuid = ? AND state = ? AND attempts = ?", job.Uuid, model.JobStateRunning, job.Attempts)
+}
+
+// leaseResult returns ErrLeaseLost when the update of a held job matched no row
+func leaseResult(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// GetLatestJob returns the most recent job of the given type for a video
+func (v *VideoRepo) GetLatestJob(videoId string, jobType string) (*model.Job, error) {
+	var job model.Job
+	err := v.db.Where("video_id = ? AND type = ?", videoId, jobType).Order("created_at desc").First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
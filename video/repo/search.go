@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"html"
+	"strings"
+
+	"github.com/iamvasanth07/showcase/video/model"
+)
+
+// SearchResult is a video matching a search query
+type SearchResult struct {
+	model.Video
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// ts_headline marks the matches with control characters, which are stripped
+// from the title and description beforehand, so that the snippets can be
+// HTML-escaped before the <mark> tags are added
+const (
+	startSel      = "\x02"
+	stopSel       = "\x03"
+	selSeparators = startSel + stopSel
+)
+
+// highlight options of the title and of the description snippets
+const (
+	titleHighlight       = "HighlightAll=true, StartSel=" + startSel + ", StopSel=" + stopSel
+	descriptionHighlight = "StartSel=" + startSel + ", StopSel=" + stopSel + ", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+)
+
+// highlightMarker escapes a snippet and turns its selections into <mark> tags
+var highlightMarker = strings.NewReplacer(startSel, "<mark>", stopSel, "</mark>")
+
+// markHighlight returns the snippet as HTML, where only the matches are marked up
+func markHighlight(snippet string) string {
+	return highlightMarker.Replace(html.EscapeString(snippet))
+}
+
+// SearchVideos returns a page of the videos matching the tsquery, parsed with
+// the given text search configuration, by decreasing rank. Only the public
+// videos ready to play are searched. It also returns the total number of
+// matches. The highlights are HTML with the matches in <mark> tags, the rest
+// of the text is escaped.
+func (v *VideoRepo) SearchVideos(config string, query string, limit int, offset int) ([]SearchResult, int64, error) {
+	var total int64
+	err := v.db.Model(&model.Video{}).
+		Where("search_vector @@ to_tsquery(?::regconfig, ?)", config, query).
+		Where("privacy = ? AND status = ?", model.VideoPrivacyPublic, model.VideoStatusReady).
+		Count(&total).Error
+	if err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// the snippets are only computed for the videos of the page
+	var results []SearchResult
+	err = v.db.Raw(`
+		SELECT videos.*, ranked.rank,
+			ts_headline(ranked.config, translate(videos.title, ?, ''), ranked.query, ?) AS title_highlight,
+			ts_headline(ranked.config, translate(videos.description, ?, ''), ranked.query, ?) AS description_highlight
+		FROM (
+			SELECT videos.uuid, q.config, q.query, ts_rank_cd(videos.search_vector, q.query, 32) AS rank
+			FROM videos, (SELECT ?::regconfig AS config, to_tsquery(?::regconfig, ?) AS query) q
+			WHERE videos.search_vector @@ q.query
+				AND videos.privacy = ? AND videos.status = ? AND videos.deleted_at IS NULL
+			ORDER BY rank DESC, videos.uuid
+			LIMIT ? OFFSET ?
+		) ranked
+		JOIN videos ON videos.uuid = ranked.uuid
+		ORDER BY ranked.rank DESC, videos.uuid`,
+		selSeparators, titleHighlight, selSeparators, descriptionHighlight, config, config, query,
+		model.VideoPrivacyPublic, model.VideoStatusReady, limit, offset,
+	).Scan(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].TitleHighlight = markHighlight(results[i].TitleHighlight)
+		results[i].DescriptionHighlight = markHighlight(results[i].DescriptionHighlight)
+	}
+	return results, total, nil
+}
@@ -0,0 +1,28 @@
+package repo
+
+import "testing"
+
+func TestMarkHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"plain", "cooking " + startSel + "pasta" + stopSel + " at home", "cooking <mark>pasta</mark> at home"},
+		{"no match", "cooking at home", "cooking at home"},
+		{
+			"script title",
+			"<script>alert(1)</script> " + startSel + "pasta" + stopSel,
+			"&lt;script&gt;alert(1)&lt;/script&gt; <mark>pasta</mark>",
+		},
+		{"mark in title", "<mark>" + startSel + "pasta" + stopSel + "</mark>", "&lt;mark&gt;<mark>pasta</mark>&lt;/mark&gt;"},
+		{"entities", `Tom & "Jerry" ` + startSel + "'s" + stopSel, "Tom &amp; &#34;Jerry&#34; <mark>&#39;s</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markHighlight(tt.snippet); got != tt.want {
+				t.Errorf("markHighlight(%q) = %q, want %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/model"
+	"gorm.io/gorm"
+)
+
+// ErrOffsetConflict is returned when the upload offset moved concurrently
+var ErrOffsetConflict = errors.New("upload offset conflict")
+
+func (v *VideoRepo) CreateUpload(upload *model.Upload) error {
+	return translate(v.db.Create(upload).Error)
+}
+
+func (v *VideoRepo) GetUpload(uploadId string) (*model.Upload, error) {
+	var upload model.Upload
+	err := v.db.First(&upload, "uuid = ?", uploadId).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &upload, nil
+}
+
+// AddUploadPart records a stored part and advances the upload offset, failing
+// with ErrOffsetConflict if the offset is no longer the one the part starts at
+func (v *VideoRepo) AddUploadPart(upload *model.Upload, part *model.UploadPart) error {
+	return v.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Upload{}).
+			Where("uuid = ? AND upload_offset = ?", upload.Uuid, part.Offset).
+			Update("upload_offset", part.Offset+part.Size)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrOffsetConflict
+		}
+		if err := tx.Create(part).Error; err != nil {
+			return err
+		}
+		upload.Offset = part.Offset + part.Size
+		return nil
+	})
+}
+
+func (v *VideoRepo) ListUploadParts(uploadId string) ([]model.UploadPart, error) {
+	var parts []model.UploadPart
+	err := v.db.Order("part_offset asc").Find(&parts, "upload_id = ?", uploadId).Error
+	if err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+func (v *VideoRepo) DeleteUploadParts(uploadId string) error {
+	return v.db.Unscoped().Delete(&model.UploadPart{}, "upload_id = ?", uploadId).Error
+}
+
+// CompleteUpload creates the video of a finished upload along with the job
+// assembling its source
+func (v *VideoRepo) CompleteUpload(upload *model.Upload, video *model.Video, jobs []*model.Job) error {
+	return translate(v.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(video).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(jobs).Error; err != nil {
+			return err
+		}
+		upload.VideoID = video.Uuid
+		return tx.Model(&model.Upload{}).Where("uuid = ?", upload.Uuid).Update("video_id", video.Uuid).Error
+	}))
+}
+
+// GetUploadByVideo returns the upload a video was created from
+func (v *VideoRepo) GetUploadByVideo(videoId string) (*model.Upload, error) {
+	var upload model.Upload
+	err := v.db.First(&upload, "video_id = ?", videoId).Error
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// FinishAssembly records the stored source of an uploaded video and its
+// technical metadata along with its processing jobs, failing with
+// gorm.ErrRecordNotFound if the video was deleted meanwhile
+func (v *VideoRepo) FinishAssembly(video *model.Video, jobs []*model.Job) error {
+	return v.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(video).
+			Select("object_key", "size", "checksum", "duration", "width", "height", "frame_rate",
+				"video_codec", "audio_codec", "bitrate", "container").
+			Updates(video)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(jobs).Error
+	})
+}
+
+func (v *VideoRepo) DeleteUpload(uploadId string) error {
+	return v.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&model.UploadPart{}, "upload_id = ?", uploadId).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Upload{}, "uuid = ?", uploadId).Error
+	})
+}
+
+// ListExpiredUploads returns the unfinished uploads that expired before t
+func (v *VideoRepo) ListExpiredUploads(t time.Time) ([]model.Upload, error) {
+	var uploads []model.Upload
+	err := v.db.Where("expires_at < ? AND video_id = ''", t).Find(&uploads).Error
+	if err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
@@ -3,6 +3,11 @@
 package repo
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
 	"github.com/iamvasanth07/showcase/video/model"
 
 	"gorm.io/gorm"
@@ -17,33 +22,216 @@ func NewVideoRepo(db *gorm.DB) *VideoRepo {
 }
 
 func (v *VideoRepo) CreateVideo(video *model.Video) error {
-	return v.db.Create(video).Error
+	return translate(v.db.Create(video).Error)
 }
 
 func (v *VideoRepo) GetVideo(videoId string) (*model.Video, error) {
 	var video model.Video
-	err := v.db.First(&video, "id = ?", videoId).Error
+	err := v.db.First(&video, "uuid = ?", videoId).Error
 	if err != nil {
 		return nil, err
 	}
 	return &video, nil
 }
 
-func (v *VideoRepo) ListVideos(limit int, offset int) ([]model.Video, error) {
+// Sort orders of the video listings
+const (
+	SortNewest     = "newest"
+	SortOldest     = "oldest"
+	SortMostViewed = "most_viewed"
+	SortLongest    = "longest"
+)
+
+// ErrInvalidCursor is returned for cursors whose key does not match the sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// sortKey is the column of a sort order, the uuid breaks the ties
+type sortKey struct {
+	column string
+	desc   bool
+	// value returns the key of a video as stored in the cursors
+	value func(video *model.Video) string
+	// parse reads the key of a cursor
+	parse func(key string) (interface{}, error)
+}
+
+var sortKeys = map[string]sortKey{
+	SortNewest:     {column: "published_at", desc: true, value: publishedKey, parse: parseTimeKey},
+	SortOldest:     {column: "published_at", desc: false, value: publishedKey, parse: parseTimeKey},
+	SortMostViewed: {column: "views", desc: true, value: viewsKey, parse: parseIntKey},
+	SortLongest:    {column: "duration", desc: true, value: durationKey, parse: parseIntKey},
+}
+
+func publishedKey(video *model.Video) string {
+	return video.PublishedAt.UTC().Format(time.RFC3339Nano)
+}
+
+func viewsKey(video *model.Video) string {
+	return strconv.FormatUint(video.Views, 10)
+}
+
+func durationKey(video *model.Video) string {
+	return strconv.FormatInt(int64(video.Duration), 10)
+}
+
+func parseTimeKey(key string) (interface{}, error) {
+	return time.Parse(time.RFC3339Nano, key)
+}
+
+func parseIntKey(key string) (interface{}, error) {
+	return strconv.ParseInt(key, 10, 64)
+}
+
+// Cursor is the position of the last video of a page, the next page starts after it
+type Cursor struct {
+	Key string
+	ID  string
+}
+
+// CursorOf returns the cursor of a video for the sort order
+func CursorOf(video *model.Video, sort string) *Cursor {
+	return &Cursor{Key: sortKeyOf(sort).value(video), ID: video.Uuid}
+}
+
+func sortKeyOf(sort string) sortKey {
+	key, ok := sortKeys[sort]
+	if !ok {
+		return sortKeys[SortNewest]
+	}
+	return key
+}
+
+// VideoFilter narrows down a video listing, zero fields are ignored
+type VideoFilter struct {
+	Category        string
+	Language        string
+	ChannelID       string
+	Privacy         string
+	Status          string
+	OwnerID         string
+	Tags            []string
+	AllTags         bool
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+}
+
+// ListVideos returns a page of the videos matching the filter and their total
+// count. The page starts after the cursor when one is given, at the offset
+// otherwise.
+func (v *VideoRepo) ListVideos(filter *VideoFilter, sort string, after *Cursor, limit int, offset int) ([]model.Video, int64, error) {
+	var total int64
+	if err := v.filterVideos(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	query, err := keyset(v.filterVideos(filter), sortKeyOf(sort), after)
+	if err != nil {
+		return nil, 0, err
+	}
+	if after == nil {
+		query = query.Offset(offset)
+	}
 	var videos []model.Video
-	err := v.db.Limit(limit).Offset(offset).Find(&videos).Error
+	if err := query.Limit(limit).Find(&videos).Error; err != nil {
+		return nil, 0, err
+	}
+	return videos, total, nil
+}
+
+// keyset orders the query by the sort key and starts it after the cursor, if any
+func keyset(query *gorm.DB, key sortKey, after *Cursor) (*gorm.DB, error) {
+	direction, comparison := "ASC", ">"
+	if key.desc {
+		direction, comparison = "DESC", "<"
+	}
+	query = query.Order(key.column + " " + direction + ", uuid " + direction)
+	if after == nil {
+		return query, nil
+	}
+	value, err := key.parse(after.Key)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidCursor
 	}
-	return videos, nil
+	return query.Where("("+key.column+", uuid) "+comparison+" (?, ?)", value, after.ID), nil
 }
 
+func (v *VideoRepo) filterVideos(filter *VideoFilter) *gorm.DB {
+	query := v.db.Model(&model.Video{})
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Language != "" {
+		query = query.Where("language = ?", filter.Language)
+	}
+	if filter.ChannelID != "" {
+		query = query.Where("channel_id = ?", filter.ChannelID)
+	}
+	if filter.Privacy != "" {
+		query = query.Where("privacy = ?", filter.Privacy)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+	if len(filter.Tags) > 0 {
+		if filter.AllTags {
+			query = query.Where("tags @> ?", jsonArray(filter.Tags...))
+		} else {
+			tags := v.db.Where("tags @> ?", jsonArray(filter.Tags[0]))
+			for _, tag := range filter.Tags[1:] {
+				tags = tags.Or("tags @> ?", jsonArray(tag))
+			}
+			query = query.Where(tags)
+		}
+	}
+	if !filter.PublishedAfter.IsZero() {
+		query = query.Where("published_at >= ?", filter.PublishedAfter)
+	}
+	if !filter.PublishedBefore.IsZero() {
+		query = query.Where("published_at < ?", filter.PublishedBefore)
+	}
+	return query
+}
+
+// jsonArray encodes strings as a json array to match jsonb columns
+func jsonArray(values ...string) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// UpdateVideo saves the editable details of a video, the search
+// configuration follows the language
 func (v *VideoRepo) UpdateVideo(video *model.Video) error {
-	return v.db.Save(video).Error
+	return translate(v.db.Model(video).
+		Select("title", "description", "category", "language", "tags", "privacy", "search_config").
+		Updates(video).Error)
 }
 
-func (v *VideoRepo) DeleteVideo(videoId string) error {
-	return v.db.Delete(&model.Video{}, "id = ?", videoId).Error
+// DeleteVideo deletes the video, cancels its pending jobs and queues the
+// cleanup job removing its files. A worker running a cancelled job loses its
+// lease.
+func (v *VideoRepo) DeleteVideo(videoId string, cleanup *model.Job) error {
+	return translate(v.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.Video{}, "uuid = ?", videoId)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		err := tx.Model(&model.Job{}).
+			Where("video_id = ? AND state IN ?", videoId, []string{model.JobStateQueued, model.JobStateRunning}).
+			Updates(map[string]interface{}{
+				"state":       model.JobStateCancelled,
+				"last_error":  "video deleted",
+				"finished_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(cleanup).Error
+	}))
 }
 
 func (v *VideoRepo) GetVideoBySlug(slug string) (*model.Video, error) {
@@ -55,11 +243,32 @@ func (v *VideoRepo) GetVideoBySlug(slug string) (*model.Video, error) {
 	return &video, nil
 }
 
-func (v *VideoRepo) GetVideoByChannelId(channelId string) ([]model.Video, error) {
-	var videos []model.Video
-	err := v.db.Find(&videos, "channel_id = ?", channelId).Error
+// GetVideoByChannelId returns the public videos of the channels that are
+// ready to play, newest first, starting after the cursor if any
+func (v *VideoRepo) GetVideoByChannelId(channelIds []string, after *Cursor, limit int) ([]model.Video, error) {
+	query := v.db.Model(&model.Video{}).
+		Where("channel_id IN ? AND privacy = ? AND status = ?", channelIds, model.VideoPrivacyPublic, model.VideoStatusReady)
+	query, err := keyset(query, sortKeys[SortNewest], after)
 	if err != nil {
 		return nil, err
 	}
+	var videos []model.Video
+	if err := query.Limit(limit).Find(&videos).Error; err != nil {
+		return nil, err
+	}
 	return videos, nil
 }
+
+// UpdateVideoFields updates the given columns of a video
+func (v *VideoRepo) UpdateVideoFields(videoId string, fields map[string]interface{}) error {
+	return v.db.Model(&model.Video{}).Where("uuid = ?", videoId).Updates(fields).Error
+}
+
+// SetGeneratedThumbnail sets the generated poster frame of a video unless its
+// owner uploaded a custom thumbnail, reporting whether it was set
+func (v *VideoRepo) SetGeneratedThumbnail(videoId string, thumbnail string) (bool, error) {
+	res := v.db.Model(&model.Video{}).
+		Where("uuid = ? AND custom_thumbnail = ?", videoId, false).
+		Update("thumbnail", thumbnail)
+	return res.RowsAffected > 0, res.Error
+}
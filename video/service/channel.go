@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/pagetoken"
+	channelpb "github.com/iamvasanth07/showcase/common/protos/channel"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/common/validation"
+	"github.com/iamvasanth07/showcase/video/config"
+	"github.com/iamvasanth07/showcase/video/repo"
+	"github.com/iamvasanth07/showcase/video/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dialChannelService connects to the channel grpc service
+func dialChannelService(settings *config.Settings) (channelpb.ChannelServiceClient, error) {
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%s", settings.Channel.GrpcHost, settings.Channel.GrcpPort), grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return channelpb.NewChannelServiceClient(conn), nil
+}
+
+// ownedChannel returns the channel with the id if the caller owns it
+func (s *VideoServer) ownedChannel(ctx context.Context, identity *auth.Identity, channelId string) (*channelpb.Channel, error) {
+	if channelId == "" {
+		v := &validation.Violations{}
+		v.Add("video.channelId", fmt.Errorf("channelId is required"))
+		return nil, v.Err()
+	}
+	res, err := s.channels.GetChannel(auth.AppendToOutgoingContext(ctx, identity), &channelpb.GetChannelRequest{Id: channelId})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		v := &validation.Violations{}
+		v.Add("video.channelId", fmt.Errorf("channel not found"))
+		return nil, v.Err()
+	case codes.Unavailable:
+		return nil, status.Error(codes.Unavailable, "channel service unavailable")
+	default:
+		s.log.Printf("failed to get channel %s: %v", channelId, err)
+		return nil, status.Error(codes.Internal, "failed to get channel")
+	}
+	if res.Channel.OwnerId != identity.UserID {
+		return nil, status.Error(codes.PermissionDenied, "the channel belongs to another user")
+	}
+	return res.Channel, nil
+}
+
+// feedParams are the listing parameters of the channel feed page tokens. The
+// tokens stay valid when the channels change between pages.
+var feedParams = pagetoken.Params("channel-feed")
+
+// GetVideoByChannelId returns the public videos of the channels, newest first
+func (s *VideoServer) GetVideoByChannelId(ctx context.Context, req *pb.GetVideoByChannelIdRequest) (*pb.GetVideoByChannelIdResponse, error) {
+	s.log.Println("Get video by channel id request received")
+	if err := utils.ValidateGetVideoByChannelId(req); err != nil {
+		return nil, err
+	}
+	_, limit := pageParams(0, req.Limit)
+	var after *repo.Cursor
+	if req.PageToken != "" {
+		token, err := s.pageTokens.Decode(req.PageToken, feedParams)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		after = &repo.Cursor{Key: token.Key, ID: token.ID}
+	}
+
+	// one more video tells whether there is a next page
+	videos, err := s.db.GetVideoByChannelId(req.ChannelIds, after, int(limit)+1)
+	if errors.Is(err, repo.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, pagetoken.ErrInvalid.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetVideoByChannelIdResponse{}
+	if len(videos) > int(limit) {
+		videos = videos[:limit]
+		cursor := repo.CursorOf(&videos[len(videos)-1], repo.SortNewest)
+		res.NextPageToken = s.pageTokens.Encode(&pagetoken.Token{Key: cursor.Key, ID: cursor.ID, Params: feedParams})
+	}
+	for i := range videos {
+		res.Videos = append(res.Videos, s.videoToProto(&videos[i]))
+	}
+	return res, nil
+}
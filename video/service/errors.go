@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/iamvasanth07/showcase/video/repo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// toStatus converts repo errors into grpc status errors, hiding anything unexpected
+func (s *VideoServer) toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, "video not found")
+	}
+	var dup *repo.DuplicateError
+	if errors.As(err, &dup) {
+		st := status.New(codes.AlreadyExists, dup.Error())
+		detailed, detailErr := st.WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "video." + dup.Field, Description: dup.Error()},
+			},
+		})
+		if detailErr != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+	s.log.Printf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
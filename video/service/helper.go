@@ -0,0 +1,61 @@
+package service
+
+import (
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/model"
+)
+
+// video model to video proto
+func VideoToProto(video *model.Video) *pb.Video {
+
+	videoProto := &pb.Video{}
+
+	if video == nil {
+		return videoProto
+	}
+	videoProto.Id = video.Uuid
+	videoProto.Title = video.Title
+	videoProto.Description = video.Description
+	videoProto.Url = video.Url
+	videoProto.ChannelId = video.ChannelID
+	videoProto.OwnerId = video.OwnerID
+	videoProto.Views = video.Views
+	videoProto.Duration = video.Duration
+	if !video.PublishedAt.IsZero() {
+		videoProto.PublishedAt = video.PublishedAt.Format(time.RFC3339)
+	}
+	videoProto.Category = video.Category
+	videoProto.Privacy = video.Privacy
+	videoProto.Language = video.Language
+	videoProto.Tags = video.Tags
+	videoProto.Slug = video.Slug
+	videoProto.Size = video.Size
+	videoProto.Checksum = video.Checksum
+	videoProto.Status = video.Status
+	videoProto.Width = video.Width
+	videoProto.Height = video.Height
+	videoProto.FrameRate = video.FrameRate
+	videoProto.VideoCodec = video.VideoCodec
+	videoProto.AudioCodec = video.AudioCodec
+	videoProto.Bitrate = video.Bitrate
+	videoProto.Container = video.Container
+	return videoProto
+}
+
+// upload model to upload proto
+func UploadToProto(upload *model.Upload) *pb.Upload {
+
+	uploadProto := &pb.Upload{}
+
+	if upload == nil {
+		return uploadProto
+	}
+	uploadProto.Id = upload.Uuid
+	uploadProto.Length = upload.Length
+	uploadProto.Offset = upload.Offset
+	uploadProto.ExpiresAt = upload.ExpiresAt.Format(time.RFC3339)
+	uploadProto.VideoId = upload.VideoID
+	return uploadProto
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// GetPlaybackUrl mints short lived signed urls to stream a video through the gateway
+func (s *VideoServer) GetPlaybackUrl(ctx context.Context, req *pb.GetPlaybackUrlRequest) (*pb.GetPlaybackUrlResponse, error) {
+	s.log.Println("Get playback url request received")
+	if req.Slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "slug is required")
+	}
+	video, err := s.viewableVideo(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if video.ObjectKey == "" {
+		return nil, status.Error(codes.FailedPrecondition, "video has no media")
+	}
+	now := time.Now()
+	res := &pb.GetPlaybackUrlResponse{}
+	var expires time.Time
+	prefix := path.Join("videos", video.Uuid) + "/"
+	res.SourceUrl, expires = s.signer.URL(video.Uuid, strings.TrimPrefix(video.ObjectKey, prefix), now)
+	if video.PlaylistKey != "" {
+		res.HlsUrl, _ = s.signer.URL(video.Uuid, strings.TrimPrefix(video.PlaylistKey, prefix), now)
+	}
+	if video.PreviewKey != "" {
+		res.PreviewUrl, _ = s.signer.URL(video.Uuid, strings.TrimPrefix(video.PreviewKey, prefix), now)
+	}
+	res.ExpiresAt = expires.Format(time.RFC3339)
+	return res, nil
+}
+
+// viewableVideo returns the video with the slug if it is ready and public or
+// unlisted, or if the caller owns it or may read any video. Hidden videos are
+// reported as not found so that their slugs are not disclosed.
+func (s *VideoServer) viewableVideo(ctx context.Context, slug string) (*model.Video, error) {
+	video, err := s.db.GetVideoBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, "video not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkViewable(ctx, video); err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// checkViewable reports a video hidden from the caller as not found
+func (s *VideoServer) checkViewable(ctx context.Context, video *model.Video) error {
+	// unlisted videos are played by anyone who has the link
+	if video.Status == model.VideoStatusReady &&
+		(video.Privacy == model.VideoPrivacyPublic || video.Privacy == model.VideoPrivacyUnlisted) {
+		return nil
+	}
+	if _, err := s.authz.AuthorizeOwner(ctx, video.OwnerID, auth.PermVideoReadAny); err != nil {
+		return status.Error(codes.NotFound, "video not found")
+	}
+	return nil
+}
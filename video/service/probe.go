@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/probe"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// spooledFile is an uploaded file written to a temporary file to be probed
+// before it is stored
+type spooledFile struct {
+	*os.File
+	size     int64
+	checksum string
+}
+
+// Remove closes and deletes the temporary file
+func (f *spooledFile) Remove() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// spool copies r to a temporary file and computes its checksum
+func spool(r io.Reader) (*spooledFile, error) {
+	f, err := os.CreateTemp("", "upload-")
+	if err != nil {
+		return nil, err
+	}
+	file := &spooledFile{File: f}
+	hash := sha256.New()
+	file.size, err = io.Copy(io.MultiWriter(f, hash), r)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Remove()
+		return nil, err
+	}
+	file.checksum = hex.EncodeToString(hash.Sum(nil))
+	return file, nil
+}
+
+// probeSource reads the technical metadata of a spooled upload into the video.
+// Unsupported files are rejected with InvalidArgument.
+func (s *VideoServer) probeSource(ctx context.Context, file *spooledFile, video *model.Video) error {
+	meta, err := s.prober.Probe(ctx, file.Name())
+	if errors.Is(err, probe.ErrUnsupported) || errors.Is(err, probe.ErrNoVideo) {
+		return status.Errorf(codes.InvalidArgument, "unsupported video file: %v", err)
+	}
+	if err != nil {
+		s.log.Printf("failed to probe video %s: %v", video.Uuid, err)
+		return status.Error(codes.Internal, "failed to read video metadata")
+	}
+	video.Duration = int32(meta.Duration.Seconds() + 0.5)
+	video.Width = int32(meta.Width)
+	video.Height = int32(meta.Height)
+	video.FrameRate = meta.FrameRate
+	video.VideoCodec = meta.VideoCodec
+	video.AudioCodec = meta.AudioCodec
+	video.Bitrate = meta.Bitrate
+	video.Container = meta.Container
+	return nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/jobs"
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/storage"
+	"github.com/iamvasanth07/showcase/video/transcoder"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// defaultMaxAttempts is used when VIDEO_SVC_JOB_MAX_ATTEMPTS is not set
+const defaultMaxAttempts = 3
+
+// progressInterval throttles the progress updates written to the database
+const progressInterval = 2 * time.Second
+
+// maxAttempts returns the number of attempts of a job
+func (s *VideoServer) maxAttempts() int {
+	if s.settings.Processing.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return s.settings.Processing.MaxAttempts
+}
+
+// processingJobs returns the transcoding and the thumbnails jobs of a newly
+// uploaded video, created along with the video
+func (s *VideoServer) processingJobs(video *model.Video) []*model.Job {
+	now := time.Now()
+	var queued []*model.Job
+	for _, jobType := range []string{model.JobTypeTranscode, model.JobTypeThumbnails} {
+		queued = append(queued, &model.Job{
+			VideoID:     video.Uuid,
+			Type:        jobType,
+			State:       model.JobStateQueued,
+			MaxAttempts: s.maxAttempts(),
+			RunAt:       now,
+		})
+	}
+	return queued
+}
+
+// cleanupDelay leaves the workers still running a job of a deleted video the
+// time to notice their lost lease before the files of the video are removed
+const cleanupDelay = 5 * time.Minute
+
+// cleanupJob returns the job removing the files of a deleted video, created
+// along with the deletion
+func (s *VideoServer) cleanupJob(videoID string) *model.Job {
+	return &model.Job{
+		VideoID:     videoID,
+		Type:        model.JobTypeCleanup,
+		State:       model.JobStateQueued,
+		MaxAttempts: s.maxAttempts(),
+		RunAt:       time.Now().Add(cleanupDelay),
+	}
+}
+
+// CleanupVideo is the job handler removing the source, the renditions and
+// the thumbnails of a deleted video, all stored below videos/<id>, and the
+// parts of its upload if it was deleted before they were assembled
+func (s *VideoServer) CleanupVideo(ctx context.Context, job *model.Job, progress func(float64)) error {
+	upload, err := s.db.GetUploadByVideo(job.VideoID)
+	if err == nil {
+		err = s.removeUpload(ctx, upload)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.storage.DeletePrefix(ctx, path.Join("videos", job.VideoID))
+}
+
+// TranscodeVideo is the job handler producing the HLS renditions of a video
+func (s *VideoServer) TranscodeVideo(ctx context.Context, job *model.Job, progress func(float64)) error {
+	err := s.transcodeVideo(ctx, job, progress)
+	if err != nil && jobs.IsFinal(job, err) {
+		if updateErr := s.db.UpdateVideoFields(job.VideoID, map[string]interface{}{
+			"status": model.VideoStatusFailed,
+		}); updateErr != nil {
+			s.log.Printf("failed to mark video %s as failed: %v", job.VideoID, updateErr)
+		}
+	}
+	return err
+}
+
+func (s *VideoServer) transcodeVideo(ctx context.Context, job *model.Job, progress func(float64)) error {
+	video, err := s.db.GetVideo(job.VideoID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	workDir, err := os.MkdirTemp("", "transcode-"+video.Uuid+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	input, err := s.downloadSource(ctx, video, workDir)
+	if err != nil {
+		return err
+	}
+	outputDir := filepath.Join(workDir, "hls")
+	result, err := s.transcoder.Transcode(ctx, input, outputDir, transcoder.DefaultLadder, throttle(progress, progressInterval))
+	if err != nil {
+		return err
+	}
+
+	prefix := path.Join("videos", video.Uuid, "hls")
+	if err := s.uploadDir(ctx, outputDir, prefix); err != nil {
+		return err
+	}
+	return s.db.UpdateVideoFields(video.Uuid, map[string]interface{}{
+		"duration":     int32(result.Duration.Round(time.Second).Seconds()),
+		"playlist_key": path.Join(prefix, result.MasterPath),
+		"status":       model.VideoStatusReady,
+	})
+}
+
+// downloadSource copies the original upload of a video into dir
+func (s *VideoServer) downloadSource(ctx context.Context, video *model.Video, dir string) (string, error) {
+	obj, _, err := s.storage.Get(ctx, video.ObjectKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", jobs.Permanent(err)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer obj.Close()
+	input := filepath.Join(dir, "source"+path.Ext(video.ObjectKey))
+	f, err := os.Create(input)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, obj); err != nil {
+		f.Close()
+		return "", err
+	}
+	return input, f.Close()
+}
+
+// uploadDir stores every file below dir under the key prefix
+func (s *VideoServer) uploadDir(ctx context.Context, dir string, prefix string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		key := path.Join(prefix, filepath.ToSlash(rel))
+		_, err = s.storage.Put(ctx, key, f, info.Size(), mime.TypeByExtension(filepath.Ext(p)))
+		return err
+	})
+}
+
+// GetProcessingStatus returns the progress of the processing of a video. The
+// videos hidden from the caller, such as the private ones and the ones still
+// processing, are only reported to their owner and the callers allowed to
+// read any video.
+func (s *VideoServer) GetProcessingStatus(ctx context.Context, req *pb.GetProcessingStatusRequest) (*pb.GetProcessingStatusResponse, error) {
+	var video *model.Video
+	var err error
+	switch {
+	case req.Id != "":
+		video, err = s.db.GetVideo(req.Id)
+	case req.Slug != "":
+		video, err = s.db.GetVideoBySlug(req.Slug)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or slug is required")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, "video not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkViewable(ctx, video); err != nil {
+		return nil, err
+	}
+	res := &pb.GetProcessingStatusResponse{
+		VideoId: video.Uuid,
+		Status:  video.Status,
+	}
+	job, err := s.db.GetLatestJob(video.Uuid, model.JobTypeTranscode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the source of a resumable upload is assembled before it is transcoded
+		job, err = s.db.GetLatestJob(video.Uuid, model.JobTypeAssemble)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.JobState = job.State
+	res.Progress = job.Progress
+	res.Attempts = int32(job.Attempts)
+	res.MaxAttempts = int32(job.MaxAttempts)
+	res.Error = job.LastError
+	if job.State == model.JobStateQueued {
+		res.NextAttemptAt = job.RunAt.Format(time.RFC3339)
+	}
+	return res, nil
+}
+
+// throttle limits the calls to progress to one per interval, always passing completion through
+func throttle(progress func(float64), interval time.Duration) func(float64) {
+	var mu sync.Mutex
+	var last time.Time
+	return func(p float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if p < 1 && time.Since(last) < interval {
+			return
+		}
+		last = time.Now()
+		progress(p)
+	}
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/jobs"
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/repo"
+	"github.com/iamvasanth07/showcase/video/storage"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// defaultUploadExpiry is used when VIDEO_SVC_UPLOAD_EXPIRY is not set
+const defaultUploadExpiry = 24 * time.Hour
+
+// partTimeout bounds the time spent storing a part after the client went away
+const partTimeout = 5 * time.Minute
+
+// CreateUpload starts a resumable upload
+func (s *VideoServer) CreateUpload(ctx context.Context, req *pb.CreateUploadRequest) (*pb.CreateUploadResponse, error) {
+	s.log.Println("Create upload request received")
+	// uploads are reserved to the users who verified their email address
+	identity, err := s.authz.AuthenticateVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meta := req.GetMetadata()
+	if meta == nil || meta.Video == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	if meta.Video.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+	if req.Length <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "length must be positive")
+	}
+	channel, err := s.ownedChannel(ctx, identity, meta.Video.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	upload := &model.Upload{
+		Length:      req.Length,
+		FileName:    meta.FileName,
+		ContentType: meta.ContentType,
+		Title:       meta.Video.Title,
+		Description: meta.Video.Description,
+		Category:    meta.Video.Category,
+		Language:    meta.Video.Language,
+		ChannelID:   channel.Id,
+		OwnerID:     identity.UserID,
+		ExpiresAt:   time.Now().Add(s.uploadExpiry()),
+	}
+	if err := s.db.CreateUpload(upload); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.CreateUploadResponse{Upload: UploadToProto(upload)}, nil
+}
+
+// GetUpload returns the offset of a resumable upload
+func (s *VideoServer) GetUpload(ctx context.Context, req *pb.GetUploadRequest) (*pb.GetUploadResponse, error) {
+	upload, err := s.findUpload(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetUploadResponse{Upload: UploadToProto(upload)}, nil
+}
+
+// AppendUpload stores the chunks of a resumable upload starting at the given
+// offset. Once every byte is received the video is created, its source is
+// assembled from the parts by a background job.
+func (s *VideoServer) AppendUpload(stream pb.VideoService_AppendUploadServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "header is required")
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the header")
+	}
+	upload, err := s.findUpload(ctx, header.Id)
+	if err != nil {
+		return err
+	}
+	if upload.Complete() {
+		// a previous attempt received every byte but failed to create the video
+		if upload.VideoID == "" {
+			if err := s.completeUpload(upload); err != nil {
+				return s.completeUploadError(upload, err)
+			}
+		}
+		return stream.SendAndClose(&pb.AppendUploadResponse{Upload: UploadToProto(upload)})
+	}
+	if header.Offset != upload.Offset {
+		return status.Errorf(codes.Aborted, "offset mismatch, expected %d", upload.Offset)
+	}
+
+	// keep the bytes received before a dropped connection so the client can
+	// resume from there: the part is stored with a context of its own
+	reader := &partReader{stream: stream, remaining: upload.Length - upload.Offset}
+	storeCtx, cancel := context.WithTimeout(context.Background(), partTimeout)
+	defer cancel()
+	part := &model.UploadPart{
+		UploadID:  upload.Uuid,
+		Offset:    upload.Offset,
+		ObjectKey: partKey(upload.Uuid, upload.Offset),
+	}
+	info, err := s.storage.Put(storeCtx, part.ObjectKey, reader, -1, "application/offset+octet-stream")
+	if err != nil {
+		s.log.Printf("failed to store part of upload %s: %v", upload.Uuid, err)
+		return status.Error(codes.Internal, "failed to store upload")
+	}
+	if reader.err != nil {
+		s.log.Printf("upload %s interrupted after %d bytes: %v", upload.Uuid, info.Size, reader.err)
+	}
+	if info.Size == 0 {
+		s.storage.Delete(storeCtx, part.ObjectKey)
+		if reader.err != nil {
+			return reader.err
+		}
+		return stream.SendAndClose(&pb.AppendUploadResponse{Upload: UploadToProto(upload)})
+	}
+	part.Size = info.Size
+	if err := s.db.AddUploadPart(upload, part); err != nil {
+		s.storage.Delete(storeCtx, part.ObjectKey)
+		if errors.Is(err, repo.ErrOffsetConflict) {
+			return status.Error(codes.Aborted, "upload offset changed concurrently")
+		}
+		return s.toStatus(err)
+	}
+	if upload.Complete() {
+		if err := s.completeUpload(upload); err != nil {
+			return s.completeUploadError(upload, err)
+		}
+	}
+	if reader.err != nil {
+		return reader.err
+	}
+	return stream.SendAndClose(&pb.AppendUploadResponse{Upload: UploadToProto(upload)})
+}
+
+// DeleteUpload terminates a resumable upload and removes the stored parts
+func (s *VideoServer) DeleteUpload(ctx context.Context, req *pb.DeleteUploadRequest) (*pb.DeleteUploadResponse, error) {
+	s.log.Println("Delete upload request received")
+	upload, err := s.findUpload(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	// an upload whose video could not be created can still be given up
+	if upload.Complete() && upload.VideoID != "" {
+		return nil, status.Error(codes.FailedPrecondition, "upload is already complete")
+	}
+	if err := s.removeUpload(ctx, upload); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &pb.DeleteUploadResponse{Id: upload.Uuid}, nil
+}
+
+// completeUpload creates the video of a finished upload along with the job
+// assembling its source, the parts can take longer to assemble than a request
+func (s *VideoServer) completeUpload(upload *model.Upload) error {
+	video := &model.Video{
+		Title:       upload.Title,
+		Description: upload.Description,
+		Category:    upload.Category,
+		Language:    upload.Language,
+		ContentType: upload.ContentType,
+		ChannelID:   upload.ChannelID,
+		OwnerID:     upload.OwnerID,
+		Size:        upload.Length,
+		Status:      model.VideoStatusProcessing,
+	}
+	video.Uuid = newVideoID()
+	return s.db.CompleteUpload(upload, video, []*model.Job{s.assemblyJob(video.Uuid)})
+}
+
+// completeUploadError reports the failure to create the video of a finished
+// upload, the next append retries it
+func (s *VideoServer) completeUploadError(upload *model.Upload, err error) error {
+	s.log.Printf("failed to complete upload %s: %v", upload.Uuid, err)
+	var dup *repo.DuplicateError
+	if errors.As(err, &dup) {
+		return s.toStatus(err)
+	}
+	return status.Error(codes.Internal, "failed to complete upload")
+}
+
+// assemblyJob returns the job assembling the source of an uploaded video
+func (s *VideoServer) assemblyJob(videoID string) *model.Job {
+	return &model.Job{
+		VideoID:     videoID,
+		Type:        model.JobTypeAssemble,
+		State:       model.JobStateQueued,
+		MaxAttempts: s.maxAttempts(),
+		RunAt:       time.Now(),
+	}
+}
+
+// AssembleUpload is the job handler concatenating the parts of a finished
+// upload into the source file of its video, then queueing the processing of
+// the video
+func (s *VideoServer) AssembleUpload(ctx context.Context, job *model.Job, progress func(float64)) error {
+	err := s.assembleUpload(ctx, job, progress)
+	if err != nil && jobs.IsFinal(job, err) {
+		if updateErr := s.db.UpdateVideoFields(job.VideoID, map[string]interface{}{
+			"status": model.VideoStatusFailed,
+		}); updateErr != nil {
+			s.log.Printf("failed to mark video %s as failed: %v", job.VideoID, updateErr)
+		}
+	}
+	return err
+}
+
+func (s *VideoServer) assembleUpload(ctx context.Context, job *model.Job, progress func(float64)) error {
+	video, err := s.db.GetVideo(job.VideoID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	upload, err := s.db.GetUploadByVideo(video.Uuid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	parts, err := s.db.ListUploadParts(upload.Uuid)
+	if err != nil {
+		return err
+	}
+	readers := make([]io.Reader, 0, len(parts))
+	for _, part := range parts {
+		obj, _, err := s.storage.Get(ctx, part.ObjectKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			return jobs.Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+		defer obj.Close()
+		readers = append(readers, obj)
+	}
+	file, err := spool(io.MultiReader(readers...))
+	if err != nil {
+		return err
+	}
+	defer file.Remove()
+	if file.size != upload.Length {
+		return jobs.Permanent(storage.ErrSizeMismatch)
+	}
+	progress(0.5)
+
+	// the files that are not videos can never be processed, their parts are
+	// removed along with the upload
+	if err := s.probeSource(ctx, file, video); err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			if removeErr := s.removeUpload(ctx, upload); removeErr != nil {
+				s.log.Printf("failed to remove rejected upload %s: %v", upload.Uuid, removeErr)
+			}
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	video.ObjectKey = sourceKey(video.Uuid, upload.FileName)
+	info, err := s.storage.Put(ctx, video.ObjectKey, file, file.size, upload.ContentType)
+	if err != nil {
+		return err
+	}
+	video.Size = info.Size
+	video.Checksum = file.checksum
+	err = s.db.FinishAssembly(video, s.processingJobs(video))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the video was deleted while its source was assembled
+		s.storage.Delete(ctx, video.ObjectKey)
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		s.storage.Delete(ctx, video.ObjectKey)
+		return err
+	}
+	for _, part := range parts {
+		if err := s.storage.Delete(ctx, part.ObjectKey); err != nil {
+			s.log.Printf("failed to remove part %s: %v", part.ObjectKey, err)
+		}
+	}
+	return s.db.DeleteUploadParts(upload.Uuid)
+}
+
+// removeUpload deletes the parts and the record of an upload
+func (s *VideoServer) removeUpload(ctx context.Context, upload *model.Upload) error {
+	parts, err := s.db.ListUploadParts(upload.Uuid)
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if err := s.storage.Delete(ctx, part.ObjectKey); err != nil {
+			return err
+		}
+	}
+	return s.db.DeleteUpload(upload.Uuid)
+}
+
+// findUpload returns an unexpired upload of the caller, the uploads of the
+// other users are reported as not found
+func (s *VideoServer) findUpload(ctx context.Context, uploadId string) (*model.Upload, error) {
+	identity, err := s.authz.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := s.db.GetUpload(uploadId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if upload.OwnerID != identity.UserID {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	if !upload.Complete() && time.Now().After(upload.ExpiresAt) {
+		return nil, status.Error(codes.NotFound, "upload expired")
+	}
+	return upload, nil
+}
+
+// partKey returns a storage key of its own to each attempt at appending a
+// part: concurrent appends at the same offset must not overwrite, nor delete
+// on conflict, the part of the attempt that won
+func partKey(uploadID string, offset int64) string {
+	return path.Join("uploads", uploadID, fmt.Sprintf("%020d-%s", offset, uuid.NewV4().String()))
+}
+
+// uploadExpiry returns how long an unfinished upload is kept
+func (s *VideoServer) uploadExpiry() time.Duration {
+	if s.settings.Upload.Expiry > 0 {
+		return time.Duration(s.settings.Upload.Expiry) * time.Hour
+	}
+	return defaultUploadExpiry
+}
+
+// CleanupExpiredUploads periodically removes the unfinished uploads that expired
+func (s *VideoServer) CleanupExpiredUploads(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		uploads, err := s.db.ListExpiredUploads(time.Now())
+		if err != nil {
+			s.log.Printf("failed to list expired uploads: %v", err)
+			continue
+		}
+		for i := range uploads {
+			if err := s.removeUpload(ctx, &uploads[i]); err != nil {
+				s.log.Printf("failed to remove expired upload %s: %v", uploads[i].Uuid, err)
+			}
+		}
+	}
+}
+
+// partReader reads the chunks of an append stream. A broken stream ends the
+// part early: the error is kept in err and the reader reports io.EOF.
+type partReader struct {
+	stream    pb.VideoService_AppendUploadServer
+	buf       []byte
+	remaining int64
+	err       error
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, io.EOF
+		}
+		req, err := r.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = err
+			return 0, io.EOF
+		}
+		if req.GetHeader() != nil {
+			r.err = status.Error(codes.InvalidArgument, "header must only be sent once")
+			return 0, io.EOF
+		}
+		r.buf = req.GetChunk()
+		if int64(len(r.buf)) > r.remaining {
+			r.buf = r.buf[:r.remaining]
+			r.err = status.Error(codes.InvalidArgument, "chunks exceed the upload length")
+		}
+		r.remaining -= int64(len(r.buf))
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
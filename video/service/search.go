@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPageSize is the page size of the listings without a limit
+const defaultPageSize = 20
+
+// SearchVideos ranks the videos matching the query over their title, tags and description
+func (s *VideoServer) SearchVideos(ctx context.Context, req *pb.SearchVideosRequest) (*pb.SearchVideosResponse, error) {
+	s.log.Println("Search videos request received")
+	if err := utils.ValidateSearchVideos(req); err != nil {
+		return nil, err
+	}
+	query, err := buildTsQuery(req.Query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, limit := pageParams(req.Page, req.Limit)
+	results, total, err := s.db.SearchVideos(model.SearchConfig(req.Language), query, int(limit), int((page-1)*limit))
+	if err != nil {
+		s.log.Printf("failed to search videos: %v", err)
+		return nil, status.Error(codes.Internal, "failed to search videos")
+	}
+	res := &pb.SearchVideosResponse{
+		Metadata: &pb.Metadata{
+			Page:  page,
+			Limit: limit,
+			Total: int32(total),
+		},
+	}
+	for i := range results {
+		res.Results = append(res.Results, &pb.SearchResult{
+			Video:                s.videoToProto(&results[i].Video),
+			Rank:                 results[i].Rank,
+			TitleHighlight:       results[i].TitleHighlight,
+			DescriptionHighlight: results[i].DescriptionHighlight,
+		})
+	}
+	return res, nil
+}
+
+// pageParams applies the defaults to the validated page and page size of a listing
+func pageParams(page int32, limit int32) (int32, int32) {
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	return page, limit
+}
+
+// queryToken is a term or a "quoted phrase" of a search query
+type queryToken struct {
+	text   string
+	phrase bool
+	negate bool
+}
+
+// tokenizeQuery splits a search query into terms and phrases
+func tokenizeQuery(q string) []queryToken {
+	var tokens []queryToken
+	for q = strings.TrimSpace(q); q != ""; q = strings.TrimSpace(q) {
+		tok := queryToken{}
+		if strings.HasPrefix(q, "-") {
+			tok.negate = true
+			q = q[1:]
+		}
+		if strings.HasPrefix(q, `"`) {
+			// an unterminated phrase runs to the end of the query
+			end := strings.Index(q[1:], `"`) + 1
+			if end == 0 {
+				end = len(q)
+			}
+			tok.text, tok.phrase = q[1:end], true
+			if end < len(q) {
+				end++
+			}
+			q = q[end:]
+		} else {
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			if end < 0 {
+				end = len(q)
+			}
+			tok.text, q = q[:end], q[end:]
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// buildTsQuery translates a search query into the to_tsquery syntax. Terms
+// are ANDed, OR binds the terms around it, phrases match adjacent words, a
+// trailing * matches prefixes and a leading - excludes a term. Only letters
+// and digits reach the tsquery.
+func buildTsQuery(q string) (string, error) {
+	var (
+		groups   [][]string
+		or       bool
+		positive bool
+	)
+	for _, tok := range tokenizeQuery(q) {
+		if !tok.phrase && !tok.negate && tok.text == "OR" {
+			or = len(groups) > 0
+			continue
+		}
+		words := strings.FieldsFunc(tok.text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			or = false
+			continue
+		}
+		lexemes := make([]string, len(words))
+		for i, word := range words {
+			lexemes[i] = "'" + strings.ToLower(word) + "'"
+		}
+		if !tok.phrase && strings.HasSuffix(tok.text, "*") {
+			lexemes[len(lexemes)-1] += ":*"
+		}
+		term := strings.Join(lexemes, " <-> ")
+		if len(lexemes) > 1 {
+			term = "(" + term + ")"
+		}
+		if tok.negate {
+			term = "!" + term
+		} else {
+			positive = true
+		}
+		if or {
+			groups[len(groups)-1] = append(groups[len(groups)-1], term)
+		} else {
+			groups = append(groups, []string{term})
+		}
+		or = false
+	}
+	if !positive {
+		return "", errors.New("query must contain a search term")
+	}
+	clauses := make([]string, len(groups))
+	for i, group := range groups {
+		clauses[i] = strings.Join(group, " | ")
+		if len(group) > 1 {
+			clauses[i] = "(" + clauses[i] + ")"
+		}
+	}
+	return strings.Join(clauses, " & "), nil
+}
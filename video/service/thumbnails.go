@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iamvasanth07/showcase/common/auth"
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/jobs"
+	"github.com/iamvasanth07/showcase/video/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// maxThumbnailSize is the largest custom thumbnail accepted
+const maxThumbnailSize = 2 << 20
+
+// thumbnailTypes maps the accepted image types to their file extension
+var thumbnailTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ThumbnailVideo is the job handler extracting the poster frame and the seek preview sprites of a video
+func (s *VideoServer) ThumbnailVideo(ctx context.Context, job *model.Job, progress func(float64)) error {
+	video, err := s.db.GetVideo(job.VideoID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	workDir, err := os.MkdirTemp("", "thumbnails-"+video.Uuid+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	input, err := s.downloadSource(ctx, video, workDir)
+	if err != nil {
+		return err
+	}
+	progress(0.5)
+	outputDir := filepath.Join(workDir, "thumbnails")
+	thumbs, err := s.transcoder.Thumbnails(ctx, input, outputDir)
+	if err != nil {
+		return err
+	}
+
+	prefix := path.Join("videos", video.Uuid, "thumbnails")
+	if err := s.uploadDir(ctx, outputDir, prefix); err != nil {
+		return err
+	}
+	err = s.db.UpdateVideoFields(video.Uuid, map[string]interface{}{
+		"preview_key": path.Join(prefix, thumbs.Preview),
+	})
+	if err != nil {
+		return err
+	}
+	// a custom thumbnail may have been uploaded while the frame was extracted,
+	// the database tells whether it can still be replaced
+	set, err := s.db.SetGeneratedThumbnail(video.Uuid, path.Join(prefix, thumbs.Poster))
+	if err != nil {
+		return err
+	}
+	if !set {
+		s.log.Printf("video %s has a custom thumbnail, the poster frame is not used", video.Uuid)
+	}
+	return nil
+}
+
+// UploadThumbnail replaces the generated poster frame of a video with a custom image
+func (s *VideoServer) UploadThumbnail(ctx context.Context, req *pb.UploadThumbnailRequest) (*pb.UploadThumbnailResponse, error) {
+	s.log.Println("Upload thumbnail request received")
+	if _, err := s.authz.Authenticate(ctx); err != nil {
+		return nil, err
+	}
+	if req.Slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "slug is required")
+	}
+	if len(req.Image) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "image is required")
+	}
+	if len(req.Image) > maxThumbnailSize {
+		return nil, status.Error(codes.InvalidArgument, "image is larger than 2 MiB")
+	}
+	// trust the content of the image rather than the declared type
+	contentType := http.DetectContentType(req.Image)
+	ext, ok := thumbnailTypes[contentType]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported image type %s", contentType)
+	}
+
+	video, err := s.authorizedVideo(ctx, req.Slug, auth.PermVideoUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	key := path.Join("videos", video.Uuid, "thumbnails", "custom"+ext)
+	if _, err := s.storage.Put(ctx, key, bytes.NewReader(req.Image), int64(len(req.Image)), contentType); err != nil {
+		s.log.Printf("failed to store thumbnail of video %s: %v", video.Uuid, err)
+		return nil, status.Error(codes.Internal, "failed to store thumbnail")
+	}
+	old := video.Thumbnail
+	video.Thumbnail = key
+	video.CustomThumbnail = true
+	err = s.db.UpdateVideoFields(video.Uuid, map[string]interface{}{
+		"thumbnail":        key,
+		"custom_thumbnail": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// remove a previous custom image stored with another extension
+	if old != "" && old != key && strings.Contains(path.Base(old), "custom") {
+		if err := s.storage.Delete(ctx, old); err != nil {
+			s.log.Printf("failed to remove thumbnail %s: %v", old, err)
+		}
+	}
+	return &pb.UploadThumbnailResponse{Video: s.videoToProto(video)}, nil
+}
+
+// videoToProto converts a video and signs the url of its thumbnail
+func (s *VideoServer) videoToProto(video *model.Video) *pb.Video {
+	videoProto := VideoToProto(video)
+	if video != nil && video.Thumbnail != "" {
+		prefix := path.Join("videos", video.Uuid) + "/"
+		videoProto.Thumbnail, _ = s.signer.URL(video.Uuid, strings.TrimPrefix(video.Thumbnail, prefix), time.Now())
+	}
+	return videoProto
+}
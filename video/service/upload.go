@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/storage"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UploadVideo receives the metadata and the chunks of a video file, stores the
+// file and creates the video
+func (s *VideoServer) UploadVideo(stream pb.VideoService_UploadVideoServer) error {
+	s.log.Println("Upload video request received")
+	ctx := stream.Context()
+	// uploads are reserved to the users who verified their email address
+	identity, err := s.authz.AuthenticateVerified(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	meta := req.GetMetadata()
+	if meta == nil || meta.Video == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the metadata")
+	}
+	if meta.Video.Title == "" {
+		return status.Error(codes.InvalidArgument, "title is required")
+	}
+	if meta.Size < 0 {
+		return status.Error(codes.InvalidArgument, "invalid size")
+	}
+	channel, err := s.ownedChannel(ctx, identity, meta.Video.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	video := &model.Video{
+		Uuid:        newVideoID(),
+		Title:       meta.Video.Title,
+		Description: meta.Video.Description,
+		Category:    meta.Video.Category,
+		Language:    meta.Video.Language,
+		ContentType: meta.ContentType,
+		Status:      model.VideoStatusProcessing,
+		ChannelID:   channel.Id,
+		OwnerID:     identity.UserID,
+	}
+	video.ObjectKey = sourceKey(video.Uuid, meta.FileName)
+
+	// the file is spooled to disk to be probed before it is stored
+	file, err := spool(&chunkReader{stream: stream})
+	if err != nil {
+		s.log.Printf("failed to receive video %s: %v", video.Uuid, err)
+		if st, ok := status.FromError(err); ok {
+			return st.Err()
+		}
+		return status.Error(codes.Internal, "failed to receive video")
+	}
+	defer file.Remove()
+	if meta.Size > 0 && file.size != meta.Size {
+		return status.Error(codes.InvalidArgument, storage.ErrSizeMismatch.Error())
+	}
+	if err := s.probeSource(ctx, file, video); err != nil {
+		return err
+	}
+	info, err := s.storage.Put(ctx, video.ObjectKey, file, file.size, meta.ContentType)
+	if err != nil {
+		s.log.Printf("failed to store video %s: %v", video.Uuid, err)
+		return status.Error(codes.Internal, "failed to store video")
+	}
+	video.Size = info.Size
+	video.Checksum = file.checksum
+
+	if err := s.db.CreateUploadedVideo(video, s.processingJobs(video)); err != nil {
+		if delErr := s.storage.Delete(ctx, video.ObjectKey); delErr != nil {
+			s.log.Printf("failed to remove orphaned object %s: %v", video.ObjectKey, delErr)
+		}
+		return s.toStatus(err)
+	}
+	return stream.SendAndClose(&pb.UploadVideoResponse{
+		Video: s.videoToProto(video),
+	})
+}
+
+// newVideoID returns the uuid of a video whose files are stored before the
+// video is created
+func newVideoID() string {
+	return uuid.NewV4().String()
+}
+
+// sourceKey returns the storage key of the original upload of a video
+func sourceKey(videoID string, fileName string) string {
+	ext := strings.ToLower(path.Ext(path.Base(fileName)))
+	return path.Join("videos", videoID, "source"+ext)
+}
+
+// chunkReader exposes the chunks of an upload stream as an io.Reader
+type chunkReader struct {
+	stream pb.VideoService_UploadVideoServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		if req.GetMetadata() != nil {
+			return 0, status.Error(codes.InvalidArgument, "metadata must only be sent once")
+		}
+		r.buf = req.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -9,13 +9,26 @@ import (
 	"log"
 	"net"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/iamvasanth07/showcase/common"
+	"github.com/iamvasanth07/showcase/common/auth"
+	"github.com/iamvasanth07/showcase/common/pagetoken"
+	channelpb "github.com/iamvasanth07/showcase/common/protos/channel"
 	pb "github.com/iamvasanth07/showcase/common/protos/video"
 	"github.com/iamvasanth07/showcase/video/config"
+	"github.com/iamvasanth07/showcase/video/jobs"
 	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/playback"
+	"github.com/iamvasanth07/showcase/video/probe"
 	"github.com/iamvasanth07/showcase/video/repo"
+	"github.com/iamvasanth07/showcase/video/storage"
+	"github.com/iamvasanth07/showcase/video/transcoder"
+	"github.com/iamvasanth07/showcase/video/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
@@ -23,137 +36,245 @@ type IVideoService interface {
 	CreateVideo(ctx context.Context, req *pb.CreateVideoRequest) (*pb.CreateVideoResponse, error)
 	GetVideo(ctx context.Context, req *pb.GetVideoRequest) (*pb.GetVideoResponse, error)
 	ListVideos(ctx context.Context, req *pb.ListVideosRequest) (*pb.ListVideosResponse, error)
+	SearchVideos(ctx context.Context, req *pb.SearchVideosRequest) (*pb.SearchVideosResponse, error)
+	GetVideoByChannelId(ctx context.Context, req *pb.GetVideoByChannelIdRequest) (*pb.GetVideoByChannelIdResponse, error)
 	UpdateVideo(ctx context.Context, req *pb.UpdateVideoRequest) (*pb.UpdateVideoResponse, error)
 	DeleteVideo(ctx context.Context, req *pb.DeleteVideoRequest) (*pb.DeleteVideoResponse, error)
+	UploadVideo(stream pb.VideoService_UploadVideoServer) error
+	CreateUpload(ctx context.Context, req *pb.CreateUploadRequest) (*pb.CreateUploadResponse, error)
+	GetUpload(ctx context.Context, req *pb.GetUploadRequest) (*pb.GetUploadResponse, error)
+	AppendUpload(stream pb.VideoService_AppendUploadServer) error
+	DeleteUpload(ctx context.Context, req *pb.DeleteUploadRequest) (*pb.DeleteUploadResponse, error)
+	GetProcessingStatus(ctx context.Context, req *pb.GetProcessingStatusRequest) (*pb.GetProcessingStatusResponse, error)
+	GetPlaybackUrl(ctx context.Context, req *pb.GetPlaybackUrlRequest) (*pb.GetPlaybackUrlResponse, error)
+	UploadThumbnail(ctx context.Context, req *pb.UploadThumbnailRequest) (*pb.UploadThumbnailResponse, error)
 }
 
 type VideoServer struct {
-	db       *repo.VideoRepo
-	log      *log.Logger
-	settings *config.Settings
+	db         *repo.VideoRepo
+	storage    storage.Storage
+	transcoder transcoder.Processor
+	prober     probe.Prober
+	signer     *playback.Signer
+	pageTokens *pagetoken.Codec
+	channels   channelpb.ChannelServiceClient
+	authz      *auth.Authorizer
+	log        *log.Logger
+	settings   *config.Settings
 	pb.UnimplementedVideoServiceServer
 }
 
-func NewVideoService(db *repo.VideoRepo, logger *log.Logger, settings *config.Settings) *VideoServer {
+func NewVideoService(db *repo.VideoRepo, store storage.Storage, tc transcoder.Processor, prober probe.Prober, channels channelpb.ChannelServiceClient, logger *log.Logger, settings *config.Settings) *VideoServer {
 	return &VideoServer{
-		db:       db,
-		log:      logger,
-		settings: settings,
+		db:         db,
+		storage:    store,
+		transcoder: tc,
+		prober:     prober,
+		channels:   channels,
+		authz:      auth.NewAuthorizer(),
+		signer:     playback.NewSigner(settings.Playback.Secret, time.Duration(settings.Playback.TTL)*time.Minute),
+		pageTokens: pagetoken.NewCodec(settings.PageToken.Secret),
+		log:        logger,
+		settings:   settings,
 	}
 }
 
 func (s *VideoServer) CreateVideo(ctx context.Context, req *pb.CreateVideoRequest) (*pb.CreateVideoResponse, error) {
 	s.log.Println("Create video request received")
+	identity, err := s.authz.AuthenticateVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Video == nil {
+		return nil, status.Error(codes.InvalidArgument, "video is required")
+	}
+	channel, err := s.ownedChannel(ctx, identity, req.Video.ChannelId)
+	if err != nil {
+		return nil, err
+	}
 	video := model.Video{
 		Title:       req.Video.Title,
 		Description: req.Video.Description,
 		Category:    req.Video.Category,
+		ChannelID:   channel.Id,
+		OwnerID:     identity.UserID,
 	}
-	err := s.db.CreateVideo(&video)
+	err = s.db.CreateVideo(&video)
 	if err != nil {
-		return nil, err
+		return nil, s.toStatus(err)
 	}
 	res := &pb.CreateVideoResponse{
-		Video: &pb.Video{
-			Id:          video.Uuid,
-			Title:       video.Title,
-			Description: video.Description,
-			Url:         video.Url,
-		},
+		Video: s.videoToProto(&video),
 	}
 	return res, nil
 }
 
 func (s *VideoServer) GetVideo(ctx context.Context, req *pb.GetVideoRequest) (*pb.GetVideoResponse, error) {
 	s.log.Println("Get video request received")
-	video, err := s.db.GetVideoBySlug(req.Slug)
+	video, err := s.viewableVideo(ctx, req.Slug)
 	if err != nil {
 		return nil, err
 	}
 	res := &pb.GetVideoResponse{
-		Video: &pb.Video{
-			Id:          video.Uuid,
-			Title:       video.Title,
-			Description: video.Description,
-			Url:         video.Url,
-		},
+		Video: s.videoToProto(video),
 	}
 	return res, nil
 }
 
 func (s *VideoServer) ListVideos(ctx context.Context, req *pb.ListVideosRequest) (*pb.ListVideosResponse, error) {
 	s.log.Println("List videos request received")
-	videos, err := s.db.ListVideos(int(req.Limit), int(req.Page))
-	if err != nil {
+	if err := utils.ValidateListVideos(req); err != nil {
 		return nil, err
 	}
-	var pbVideos []*pb.Video
-	for _, video := range videos {
-		pbVideos = append(pbVideos, &pb.Video{
-			Id:          video.Uuid,
-			Title:       video.Title,
-			Description: video.Description,
-			Url:         video.Url,
-		})
+	filter := &repo.VideoFilter{
+		Category:  req.Category,
+		Language:  req.Language,
+		ChannelID: req.ChannelId,
+		Privacy:   req.Privacy,
+		Tags:      req.Tags,
+		AllTags:   req.TagMatch == "all",
+	}
+	if filter.Privacy == "" {
+		filter.Privacy = model.VideoPrivacyPublic
+	}
+	// the public listing only holds the videos ready to play, as GetVideo
+	// hides the others. The other videos are listed to their owner and the
+	// callers allowed to read any video.
+	if filter.Privacy == model.VideoPrivacyPublic {
+		filter.Status = model.VideoStatusReady
+	} else {
+		identity, err := s.authz.Authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.authz.Require(ctx, auth.PermVideoReadAny); err != nil {
+			filter.OwnerID = identity.UserID
+		}
+	}
+	filter.PublishedAfter, _ = utils.ParseTime(req.PublishedAfter)
+	filter.PublishedBefore, _ = utils.ParseTime(req.PublishedBefore)
+	sort := req.Sort
+	if sort == "" {
+		sort = repo.SortNewest
+	}
+	params := pagetoken.Params(req.Category, req.Language, req.ChannelId, strings.Join(req.Tags, ","),
+		req.TagMatch, req.Privacy, req.PublishedAfter, req.PublishedBefore, sort)
+	page, limit := pageParams(req.Page, req.Limit)
+	var after *repo.Cursor
+	if req.PageToken != "" {
+		token, err := s.pageTokens.Decode(req.PageToken, params)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		after = &repo.Cursor{Key: token.Key, ID: token.ID}
+		page = 0
+	}
+
+	// one more video tells whether there is a next page
+	videos, total, err := s.db.ListVideos(filter, sort, after, int(limit)+1, int((page-1)*limit))
+	if errors.Is(err, repo.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, pagetoken.ErrInvalid.Error())
+	}
+	if err != nil {
+		return nil, err
 	}
 	res := &pb.ListVideosResponse{
-		Videos: pbVideos,
+		Metadata: &pb.Metadata{
+			Page:  page,
+			Limit: limit,
+			Total: int32(total),
+		},
+	}
+	if len(videos) > int(limit) {
+		videos = videos[:limit]
+		cursor := repo.CursorOf(&videos[len(videos)-1], sort)
+		res.NextPageToken = s.pageTokens.Encode(&pagetoken.Token{Key: cursor.Key, ID: cursor.ID, Params: params})
+	}
+	for i := range videos {
+		res.Videos = append(res.Videos, s.videoToProto(&videos[i]))
 	}
 	return res, nil
 }
 
+// UpdateVideo changes the details of a video, the fields left empty are kept
 func (s *VideoServer) UpdateVideo(ctx context.Context, req *pb.UpdateVideoRequest) (*pb.UpdateVideoResponse, error) {
 	s.log.Println("Update video request received")
-
-	// Check if video exists
-	video, err := s.db.GetVideoBySlug(req.Video.Slug)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New(fmt.Sprintf("Video with id %s not found", req.Video.Id))
-		}
+	if err := utils.ValidateUpdateVideo(req); err != nil {
 		return nil, err
 	}
-
-	video = &model.Video{
-		Uuid:        req.Video.Id,
-		Title:       req.Video.Title,
-		Description: req.Video.Description,
-		Url:         req.Video.Url,
-	}
-
-	err = s.db.UpdateVideo(video)
+	video, err := s.authorizedVideo(ctx, req.Video.Slug, auth.PermVideoUpdateAny)
 	if err != nil {
 		return nil, err
 	}
+	if req.Video.Title != "" {
+		video.Title = req.Video.Title
+	}
+	if req.Video.Description != "" {
+		video.Description = req.Video.Description
+	}
+	if req.Video.Category != "" {
+		video.Category = req.Video.Category
+	}
+	if req.Video.Language != "" {
+		video.Language = req.Video.Language
+	}
+	if req.Video.Tags != nil {
+		video.Tags = req.Video.Tags
+	}
+	if req.Video.Privacy != "" {
+		video.Privacy = req.Video.Privacy
+	}
+	if err := s.db.UpdateVideo(video); err != nil {
+		return nil, s.toStatus(err)
+	}
 	res := &pb.UpdateVideoResponse{
-		Video: &pb.Video{
-			Id:          video.Uuid,
-			Title:       video.Title,
-			Description: video.Description,
-			Url:         video.Url,
-		},
+		Video: s.videoToProto(video),
 	}
 	return res, nil
 }
 
+// DeleteVideo deletes a video of the caller
 func (s *VideoServer) DeleteVideo(ctx context.Context, req *pb.DeleteVideoRequest) (*pb.DeleteVideoResponse, error) {
 	s.log.Println("Delete video request received")
-	err := s.db.DeleteVideo(req.Slug)
+	video, err := s.authorizedVideo(ctx, req.Slug, auth.PermVideoDeleteAny)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.db.DeleteVideo(video.Uuid, s.cleanupJob(video.Uuid)); err != nil {
+		return nil, s.toStatus(err)
+	}
 	res := &pb.DeleteVideoResponse{
 		Video: &pb.Video{
-			Slug: req.Slug,
+			Id:   video.Uuid,
+			Slug: video.Slug,
 		},
 	}
 	return res, nil
 }
 
+// authorizedVideo returns the video with the slug if the caller owns it or
+// holds the permission
+func (s *VideoServer) authorizedVideo(ctx context.Context, slug string, permission string) (*model.Video, error) {
+	if _, err := s.authz.Authenticate(ctx); err != nil {
+		return nil, err
+	}
+	video, err := s.db.GetVideoBySlug(slug)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if _, err := s.authz.AuthorizeOwner(ctx, video.OwnerID, permission); err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
 func RunServer() {
 
 	logger := log.New(os.Stdout, "video-service: ", log.LstdFlags)
 	settings := config.GetSettings()
+	if missing := settings.MissingSecrets(); len(missing) > 0 {
+		log.Fatalf("refusing to start without the secrets %s", strings.Join(missing, ", "))
+	}
 	logger.Println("Initializing video service with settings...")
 	logger.Printf("%v, %v, %v", settings.Database, settings.Server, settings.Logger)
 	conn, err := initDB(settings)
@@ -166,18 +287,69 @@ func RunServer() {
 		log.Fatalf("failed to migrate db: %v", err)
 	}
 	db := repo.NewVideoRepo(conn)
-	runGRPCServer(settings, db, logger)
+	store, err := storage.New(settings)
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %v", err)
+	}
+	tc, err := transcoder.New(settings)
+	if err != nil {
+		log.Fatalf("failed to initialize transcoder: %v", err)
+	}
+	prober, err := probe.New(settings)
+	if err != nil {
+		log.Fatalf("failed to initialize prober: %v", err)
+	}
+	channels, err := dialChannelService(settings)
+	if err != nil {
+		log.Fatalf("failed to connect to the channel service: %v", err)
+	}
+	runGRPCServer(settings, db, store, tc, prober, channels, logger)
 
 }
 
 func migrateDB(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&model.Video{},
+		&model.Upload{},
+		&model.UploadPart{},
+		&model.Job{},
 	)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range searchMigrations {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchMigrations add the full-text search vector of the videos. Title, tags
+// and description are weighted in this order, each indexed both stemmed in
+// the language of the video and unstemmed so that queries in any language
+// still find exact words.
+var searchMigrations = []string{
+	`ALTER TABLE videos ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT 'simple'`,
+	`ALTER TABLE videos ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector(search_config, coalesce(title, '')) || to_tsvector('simple', coalesce(title, '')), 'A') ||
+		setweight(jsonb_to_tsvector(search_config, coalesce(tags, '[]'), '["string"]') || jsonb_to_tsvector('simple', coalesce(tags, '[]'), '["string"]'), 'B') ||
+		setweight(to_tsvector(search_config, coalesce(description, '')) || to_tsvector('simple', coalesce(description, '')), 'C')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_videos_search_vector ON videos USING GIN (search_vector)`,
 }
 
-func runGRPCServer(settings *config.Settings, db *repo.VideoRepo, logger *log.Logger) {
-	videoServer := NewVideoService(db, logger, settings)
+func runGRPCServer(settings *config.Settings, db *repo.VideoRepo, store storage.Storage, tc transcoder.Processor, prober probe.Prober, channels channelpb.ChannelServiceClient, logger *log.Logger) {
+	videoServer := NewVideoService(db, store, tc, prober, channels, logger, settings)
+	go videoServer.CleanupExpiredUploads(context.Background(), time.Hour)
+
+	// background processing of the uploaded videos
+	worker := jobs.NewWorker(db, logger, settings.Processing.Workers)
+	worker.Handle(model.JobTypeTranscode, videoServer.TranscodeVideo)
+	worker.Handle(model.JobTypeThumbnails, videoServer.ThumbnailVideo)
+	worker.Handle(model.JobTypeCleanup, videoServer.CleanupVideo)
+	worker.Handle(model.JobTypeAssemble, videoServer.AssembleUpload)
+	go worker.Run(context.Background())
 	var opts []grpc.ServerOption
 	s := grpc.NewServer(opts...)
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", settings.Server.GrpcHost, settings.Server.GrcpPort))
@@ -0,0 +1,66 @@
+// package storage stores the media files of the video service
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/config"
+)
+
+var (
+	// ErrNotFound is returned when no object exists for the key
+	ErrNotFound = errors.New("object not found")
+	// ErrSizeMismatch is returned when the stored content differs from the announced size
+	ErrSizeMismatch = errors.New("size mismatch")
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Object is an open stored object that supports seeking for range reads
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// Storage is a blob store for video files
+type Storage interface {
+	// Put stores the content of r under key. size is -1 when unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*ObjectInfo, error)
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (Object, *ObjectInfo, error)
+	// Stat returns the metadata of the object stored under key
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object whose key is below the prefix, as
+	// in prefix/...
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+func init() {
+	// content types of the HLS output, missing from most mime.types files
+	mime.AddExtensionType(".m3u8", "application/vnd.apple.mpegurl")
+	mime.AddExtensionType(".ts", "video/mp2t")
+}
+
+// New returns the storage backend selected in the settings
+func New(settings *config.Settings) (Storage, error) {
+	switch settings.Storage.Backend {
+	case "", "local":
+		return NewLocalStorage(settings.Storage.LocalPath)
+	case "s3":
+		return NewS3Storage(settings.Storage.S3)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", settings.Storage.Backend)
+}
@@ -0,0 +1,86 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FakeTranscoder writes placeholder playlists and segments without decoding
+// the input. It is meant for tests and for local development without ffmpeg.
+type FakeTranscoder struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+	Err      error
+}
+
+// NewFakeTranscoder returns a fake transcoder for a 1080p source of the given duration
+func NewFakeTranscoder(duration time.Duration) *FakeTranscoder {
+	return &FakeTranscoder{Duration: duration, Width: 1920, Height: 1080}
+}
+
+func (f *FakeTranscoder) Transcode(ctx context.Context, input string, outputDir string, ladder []Rendition, progress func(float64)) (*Result, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if _, err := os.Stat(input); err != nil {
+		return nil, err
+	}
+	renditions := fitLadder(ladder, f.Height)
+	result := &Result{Duration: f.Duration, MasterPath: MasterPlaylist}
+	for i, r := range renditions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(outputDir, r.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		seconds := f.Duration.Seconds()
+		segment := fmt.Sprintf("#EXTINF:%.3f,\nsegment_00000.ts\n", seconds)
+		playlist := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-PLAYLIST-TYPE:VOD\n" +
+			fmt.Sprintf("#EXT-X-TARGETDURATION:%d\n", int(seconds)+1) + segment + "#EXT-X-ENDLIST\n"
+		if err := os.WriteFile(filepath.Join(dir, "index.m3u8"), []byte(playlist), 0o644); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "segment_00000.ts"), nil, 0o644); err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, Output{
+			Rendition: r,
+			Width:     scaledWidth(f.Width, f.Height, r.Height),
+			Playlist:  r.Name + "/index.m3u8",
+		})
+		if progress != nil {
+			progress(float64(i+1) / float64(len(renditions)))
+		}
+	}
+	err := os.WriteFile(filepath.Join(outputDir, MasterPlaylist), []byte(masterPlaylist(result.Outputs)), 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f *FakeTranscoder) Thumbnails(ctx context.Context, input string, outputDir string) (*Thumbnails, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+	thumbs := &Thumbnails{Poster: PosterFile, Sprites: []string{"sprite_001.jpg"}, Preview: PreviewFile}
+	for _, name := range []string{thumbs.Poster, thumbs.Sprites[0]} {
+		if err := os.WriteFile(filepath.Join(outputDir, name), nil, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	vtt := previewVTT(f.Duration, previewInterval(f.Duration), thumbs.Sprites)
+	if err := os.WriteFile(filepath.Join(outputDir, PreviewFile), []byte(vtt), 0o644); err != nil {
+		return nil, err
+	}
+	return thumbs, nil
+}
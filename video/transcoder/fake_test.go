@@ -0,0 +1,140 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeInput(t *testing.T) string {
+	t.Helper()
+	input := filepath.Join(t.TempDir(), "source.mp4")
+	if err := os.WriteFile(input, []byte("not really a video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestFakeTranscoderWritesRenditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		renditions []string
+	}{
+		{name: "1080p source", width: 1920, height: 1080, renditions: []string{"1080p", "720p", "480p", "360p"}},
+		{name: "720p source", width: 1280, height: 720, renditions: []string{"720p", "480p", "360p"}},
+		{name: "source below the ladder", width: 320, height: 240, renditions: []string{"360p"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &FakeTranscoder{Duration: 42 * time.Second, Width: tt.width, Height: tt.height}
+			outputDir := filepath.Join(t.TempDir(), "hls")
+			var progress []float64
+			result, err := fake.Transcode(context.Background(), writeInput(t), outputDir, DefaultLadder, func(p float64) {
+				progress = append(progress, p)
+			})
+			if err != nil {
+				t.Fatalf("Transcode() error = %v", err)
+			}
+			if result.Duration != fake.Duration {
+				t.Errorf("Duration = %v, want %v", result.Duration, fake.Duration)
+			}
+			if len(result.Outputs) != len(tt.renditions) {
+				t.Fatalf("got %d outputs, want %d", len(result.Outputs), len(tt.renditions))
+			}
+			master, err := os.ReadFile(filepath.Join(outputDir, result.MasterPath))
+			if err != nil {
+				t.Fatalf("master playlist: %v", err)
+			}
+			for i, name := range tt.renditions {
+				out := result.Outputs[i]
+				if out.Name != name {
+					t.Errorf("output %d = %s, want %s", i, out.Name, name)
+				}
+				if out.Width%2 != 0 {
+					t.Errorf("output %s has an odd width %d", name, out.Width)
+				}
+				if _, err := os.Stat(filepath.Join(outputDir, out.Playlist)); err != nil {
+					t.Errorf("media playlist of %s: %v", name, err)
+				}
+				if !strings.Contains(string(master), out.Playlist) {
+					t.Errorf("master playlist does not reference %s", out.Playlist)
+				}
+			}
+			if len(progress) != len(tt.renditions) || progress[len(progress)-1] != 1 {
+				t.Errorf("progress = %v, want one report per rendition ending at 1", progress)
+			}
+		})
+	}
+}
+
+func TestFakeTranscoderFailures(t *testing.T) {
+	errBroken := errors.New("broken transcoder")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name  string
+		fake  *FakeTranscoder
+		ctx   context.Context
+		input func(t *testing.T) string
+		want  error
+	}{
+		{
+			name:  "configured error",
+			fake:  &FakeTranscoder{Duration: time.Second, Width: 1920, Height: 1080, Err: errBroken},
+			ctx:   context.Background(),
+			input: writeInput,
+			want:  errBroken,
+		},
+		{
+			name:  "cancelled context",
+			fake:  NewFakeTranscoder(time.Second),
+			ctx:   cancelled,
+			input: writeInput,
+			want:  context.Canceled,
+		},
+		{
+			name: "missing input",
+			fake: NewFakeTranscoder(time.Second),
+			ctx:  context.Background(),
+			input: func(t *testing.T) string {
+				return filepath.Join(t.TempDir(), "missing.mp4")
+			},
+			want: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fake.Transcode(tt.ctx, tt.input(t), t.TempDir(), DefaultLadder, nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Transcode() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeTranscoderThumbnails(t *testing.T) {
+	fake := NewFakeTranscoder(90 * time.Second)
+	outputDir := filepath.Join(t.TempDir(), "thumbnails")
+	thumbs, err := fake.Thumbnails(context.Background(), writeInput(t), outputDir)
+	if err != nil {
+		t.Fatalf("Thumbnails() error = %v", err)
+	}
+	for _, name := range append([]string{thumbs.Poster, thumbs.Preview}, thumbs.Sprites...) {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("thumbnail file %s: %v", name, err)
+		}
+	}
+	vtt, err := os.ReadFile(filepath.Join(outputDir, thumbs.Preview))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(vtt), "WEBVTT") {
+		t.Errorf("preview track does not start with WEBVTT: %q", vtt)
+	}
+}
@@ -0,0 +1,234 @@
+package transcoder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// segmentDuration is the target duration of the HLS segments in seconds
+const segmentDuration = 6
+
+// FFmpegTranscoder transcodes with the ffmpeg and ffprobe executables
+type FFmpegTranscoder struct {
+	ffmpeg  string
+	ffprobe string
+}
+
+// NewFFmpegTranscoder returns a new ffmpeg transcoder. Empty paths are looked up in $PATH.
+func NewFFmpegTranscoder(ffmpegPath string, ffprobePath string) *FFmpegTranscoder {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	return &FFmpegTranscoder{ffmpeg: ffmpegPath, ffprobe: ffprobePath}
+}
+
+func (f *FFmpegTranscoder) Transcode(ctx context.Context, input string, outputDir string, ladder []Rendition, progress func(float64)) (*Result, error) {
+	width, height, duration, err := f.probe(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	renditions := fitLadder(ladder, height)
+	result := &Result{Duration: duration, MasterPath: MasterPlaylist}
+	for i, r := range renditions {
+		dir := filepath.Join(outputDir, r.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		report := func(p float64) {
+			if progress != nil {
+				progress((float64(i) + p) / float64(len(renditions)))
+			}
+		}
+		if err := f.encode(ctx, input, dir, r, duration, report); err != nil {
+			return nil, fmt.Errorf("transcoding %s: %w", r.Name, err)
+		}
+		result.Outputs = append(result.Outputs, Output{
+			Rendition: r,
+			Width:     scaledWidth(width, height, r.Height),
+			Playlist:  r.Name + "/index.m3u8",
+		})
+	}
+	err = os.WriteFile(filepath.Join(outputDir, MasterPlaylist), []byte(masterPlaylist(result.Outputs)), 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encode writes the HLS playlist and segments of a single rendition
+func (f *FFmpegTranscoder) encode(ctx context.Context, input string, dir string, r Rendition, duration time.Duration, progress func(float64)) error {
+	args := []string{
+		"-hide_banner", "-nostats", "-y",
+		"-i", input,
+		"-vf", fmt.Sprintf("scale=-2:%d", r.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-b:v", fmt.Sprintf("%dk", r.VideoBitrate),
+		"-maxrate", fmt.Sprintf("%dk", r.VideoBitrate*107/100),
+		"-bufsize", fmt.Sprintf("%dk", r.VideoBitrate*3/2),
+		"-g", strconv.Itoa(segmentDuration * 30), "-sc_threshold", "0",
+		"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", r.AudioBitrate), "-ac", "2",
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(segmentDuration),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(dir, "segment_%05d.ts"),
+		"-progress", "pipe:1",
+		filepath.Join(dir, "index.m3u8"),
+	}
+	cmd := exec.CommandContext(ctx, f.ffmpeg, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		if key != "out_time_us" || duration <= 0 {
+			continue
+		}
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		p := float64(us) / float64(duration.Microseconds())
+		if p > 1 {
+			p = 1
+		}
+		progress(p)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(stderr.String()))
+	}
+	return nil
+}
+
+// probe returns the dimensions of the first video stream and the duration of the input
+func (f *FFmpegTranscoder) probe(ctx context.Context, input string) (int, int, time.Duration, error) {
+	out, err := exec.CommandContext(ctx, f.ffprobe,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		input,
+	).Output()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("ffprobe: %w", err)
+	}
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return 0, 0, 0, fmt.Errorf("ffprobe: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, 0, 0, fmt.Errorf("ffprobe: no video stream")
+	}
+	seconds, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	return probe.Streams[0].Width, probe.Streams[0].Height, time.Duration(seconds * float64(time.Second)), nil
+}
+
+// fitLadder drops the renditions above the source height, keeping at least the smallest one
+func fitLadder(ladder []Rendition, height int) []Rendition {
+	var fit []Rendition
+	for _, r := range ladder {
+		if r.Height <= height {
+			fit = append(fit, r)
+		}
+	}
+	if len(fit) == 0 && len(ladder) > 0 {
+		smallest := ladder[0]
+		for _, r := range ladder {
+			if r.Height < smallest.Height {
+				smallest = r
+			}
+		}
+		fit = append(fit, smallest)
+	}
+	return fit
+}
+
+// scaledWidth returns the even width matching targetHeight at the source aspect ratio
+func scaledWidth(width int, height int, targetHeight int) int {
+	if height == 0 {
+		return 0
+	}
+	w := width * targetHeight / height
+	return w + w%2
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+func (f *FFmpegTranscoder) Thumbnails(ctx context.Context, input string, outputDir string) (*Thumbnails, error) {
+	_, _, duration, err := f.probe(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+	thumbs := &Thumbnails{Poster: PosterFile, Preview: PreviewFile}
+
+	poster := exec.CommandContext(ctx, f.ffmpeg,
+		"-hide_banner", "-nostats", "-y",
+		"-ss", fmt.Sprintf("%.3f", posterOffset(duration).Seconds()),
+		"-i", input,
+		"-frames:v", "1",
+		"-vf", "scale=1280:-2",
+		"-q:v", "3",
+		filepath.Join(outputDir, PosterFile),
+	)
+	if out, err := poster.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("poster: %w: %s", err, lastLine(string(out)))
+	}
+
+	interval := previewInterval(duration)
+	filter := fmt.Sprintf("fps=1/%d,scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,tile=%dx%d",
+		int(interval.Seconds()), tileWidth, tileHeight, tileWidth, tileHeight, tileColumns, tileRows)
+	sprite := exec.CommandContext(ctx, f.ffmpeg,
+		"-hide_banner", "-nostats", "-y",
+		"-i", input,
+		"-vf", filter,
+		"-q:v", "5",
+		filepath.Join(outputDir, "sprite_%03d.jpg"),
+	)
+	if out, err := sprite.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("sprite: %w: %s", err, lastLine(string(out)))
+	}
+	matches, err := filepath.Glob(filepath.Join(outputDir, "sprite_*.jpg"))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		thumbs.Sprites = append(thumbs.Sprites, filepath.Base(m))
+	}
+	vtt := previewVTT(duration, interval, thumbs.Sprites)
+	if err := os.WriteFile(filepath.Join(outputDir, PreviewFile), []byte(vtt), 0o644); err != nil {
+		return nil, err
+	}
+	return thumbs, nil
+}
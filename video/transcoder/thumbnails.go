@@ -0,0 +1,74 @@
+package transcoder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// file names of the thumbnails in the output directory
+const (
+	PosterFile  = "poster.jpg"
+	PreviewFile = "preview.vtt"
+)
+
+// sprite sheet layout of the seek previews
+const (
+	tileWidth   = 160
+	tileHeight  = 90
+	tileColumns = 10
+	tileRows    = 10
+	maxTiles    = 200
+)
+
+// Thumbnails describes the images written to the output directory
+type Thumbnails struct {
+	Poster  string   // path of the poster frame relative to the output directory
+	Sprites []string // paths of the sprite sheets relative to the output directory
+	Preview string   // path of the WebVTT index of the sprite sheets
+}
+
+// previewInterval spreads at most maxTiles previews over the duration, one per second at least
+func previewInterval(duration time.Duration) time.Duration {
+	interval := duration / maxTiles
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return interval.Round(time.Second)
+}
+
+// posterOffset returns the time of the poster frame, skipping intros and black frames
+func posterOffset(duration time.Duration) time.Duration {
+	return duration / 10
+}
+
+// previewVTT returns the WebVTT cues mapping time ranges to sprite sheet tiles
+func previewVTT(duration time.Duration, interval time.Duration, sprites []string) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	perSheet := tileColumns * tileRows
+	for i := 0; time.Duration(i)*interval < duration; i++ {
+		sheet := i / perSheet
+		if sheet >= len(sprites) {
+			break
+		}
+		tile := i % perSheet
+		start := time.Duration(i) * interval
+		end := start + interval
+		if end > duration {
+			end = duration
+		}
+		fmt.Fprintf(&b, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			vttTimestamp(start), vttTimestamp(end), sprites[sheet],
+			(tile%tileColumns)*tileWidth, (tile/tileColumns)*tileHeight, tileWidth, tileHeight)
+	}
+	return b.String()
+}
+
+func vttTimestamp(d time.Duration) string {
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
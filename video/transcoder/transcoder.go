@@ -0,0 +1,85 @@
+// package transcoder turns uploaded videos into HLS renditions
+
+package transcoder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iamvasanth07/showcase/video/config"
+)
+
+// MasterPlaylist is the file name of the HLS master playlist in the output directory
+const MasterPlaylist = "master.m3u8"
+
+// Rendition is a target resolution and bitrate of the HLS ladder
+type Rendition struct {
+	Name         string
+	Height       int
+	VideoBitrate int // kbit/s
+	AudioBitrate int // kbit/s
+}
+
+// DefaultLadder is the set of renditions produced for every video
+var DefaultLadder = []Rendition{
+	{Name: "1080p", Height: 1080, VideoBitrate: 5000, AudioBitrate: 192},
+	{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	{Name: "480p", Height: 480, VideoBitrate: 1400, AudioBitrate: 128},
+	{Name: "360p", Height: 360, VideoBitrate: 800, AudioBitrate: 96},
+}
+
+// Output is a rendition produced by a transcoder
+type Output struct {
+	Rendition
+	Width    int
+	Playlist string // path of the media playlist relative to the output directory
+}
+
+// Result describes the files written to the output directory
+type Result struct {
+	Duration   time.Duration
+	Outputs    []Output
+	MasterPath string // path of the master playlist relative to the output directory
+}
+
+// Transcoder transcodes the input file into HLS renditions below outputDir.
+// progress reports the completion between 0 and 1.
+type Transcoder interface {
+	Transcode(ctx context.Context, input string, outputDir string, ladder []Rendition, progress func(float64)) (*Result, error)
+}
+
+// Thumbnailer extracts a poster frame and the seek preview sprite sheets of
+// the input file into outputDir
+type Thumbnailer interface {
+	Thumbnails(ctx context.Context, input string, outputDir string) (*Thumbnails, error)
+}
+
+// Processor transcodes videos and extracts their thumbnails
+type Processor interface {
+	Transcoder
+	Thumbnailer
+}
+
+// New returns the transcoder selected in the settings
+func New(settings *config.Settings) (Processor, error) {
+	switch settings.Processing.Transcoder {
+	case "", "ffmpeg":
+		return NewFFmpegTranscoder(settings.Processing.FFmpegPath, settings.Processing.FFprobePath), nil
+	case "fake":
+		return NewFakeTranscoder(10 * time.Second), nil
+	}
+	return nil, fmt.Errorf("unknown transcoder %q", settings.Processing.Transcoder)
+}
+
+// masterPlaylist returns the HLS master playlist referencing the outputs
+func masterPlaylist(outputs []Output) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, o := range outputs {
+		bandwidth := (o.VideoBitrate + o.AudioBitrate) * 1000
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n%s\n", bandwidth, o.Width, o.Height, o.Name, o.Playlist)
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/iamvasanth07/showcase/common/protos/video"
+	"github.com/iamvasanth07/showcase/common/validation"
+	"github.com/iamvasanth07/showcase/video/model"
+	"github.com/iamvasanth07/showcase/video/repo"
+)
+
+// MaxPageSize is the largest page of a listing
+const MaxPageSize = 100
+
+// maxFilterTags bounds the tags of a listing filter
+const maxFilterTags = 10
+
+// MaxQueryLength bounds the length of search queries
+const MaxQueryLength = 256
+
+// ValidatePage validates the 1-based page of a listing, 0 is the first page
+func ValidatePage(page int32) error {
+	if page < 0 {
+		return fmt.Errorf("page must not be negative")
+	}
+	return nil
+}
+
+// ValidateLimit validates the page size of a listing, 0 is the default size
+func ValidateLimit(limit int32) error {
+	if limit < 0 || limit > MaxPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+	return nil
+}
+
+// ValidateSort validates the sort order of a video listing
+func ValidateSort(sort string) error {
+	switch sort {
+	case "", repo.SortNewest, repo.SortOldest, repo.SortMostViewed, repo.SortLongest:
+		return nil
+	}
+	return fmt.Errorf("sort must be one of %s, %s, %s or %s", repo.SortNewest, repo.SortOldest, repo.SortMostViewed, repo.SortLongest)
+}
+
+// ValidatePrivacy validates the privacy of a video
+func ValidatePrivacy(privacy string) error {
+	switch privacy {
+	case "", model.VideoPrivacyPublic, model.VideoPrivacyUnlisted, model.VideoPrivacyPrivate:
+		return nil
+	}
+	return fmt.Errorf("privacy must be one of %s, %s or %s", model.VideoPrivacyPublic, model.VideoPrivacyUnlisted, model.VideoPrivacyPrivate)
+}
+
+// ParseTime parses an optional RFC 3339 timestamp
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an RFC 3339 timestamp")
+	}
+	return t, nil
+}
+
+// ValidateListVideos validates the filters, the sort order and the page of a listing
+func ValidateListVideos(req *pb.ListVideosRequest) error {
+	var v validation.Violations
+	v.Add("page", ValidatePage(req.Page))
+	v.Add("limit", ValidateLimit(req.Limit))
+	v.Add("sort", ValidateSort(req.Sort))
+	v.Add("privacy", ValidatePrivacy(req.Privacy))
+	if len(req.Tags) > maxFilterTags {
+		v.Add("tags", fmt.Errorf("at most %d tags can be filtered", maxFilterTags))
+	}
+	switch req.TagMatch {
+	case "", "any", "all":
+	default:
+		v.Add("tagMatch", fmt.Errorf("tagMatch must be any or all"))
+	}
+	after, err := ParseTime(req.PublishedAfter)
+	v.Add("publishedAfter", err)
+	before, err := ParseTime(req.PublishedBefore)
+	v.Add("publishedBefore", err)
+	if !after.IsZero() && !before.IsZero() && before.Before(after) {
+		v.Add("publishedBefore", fmt.Errorf("publishedBefore must not be before publishedAfter"))
+	}
+	return v.Err()
+}
+
+// ValidateUpdateVideo validates the fields of a video update
+func ValidateUpdateVideo(req *pb.UpdateVideoRequest) error {
+	var v validation.Violations
+	if req.Video == nil || req.Video.Slug == "" {
+		v.Add("video.slug", fmt.Errorf("slug is required"))
+		return v.Err()
+	}
+	v.Add("video.privacy", ValidatePrivacy(req.Video.Privacy))
+	return v.Err()
+}
+
+// MaxFeedChannels bounds the channels of a channel feed
+const MaxFeedChannels = 500
+
+// ValidateGetVideoByChannelId validates the channels and the page size of a channel feed
+func ValidateGetVideoByChannelId(req *pb.GetVideoByChannelIdRequest) error {
+	var v validation.Violations
+	if len(req.ChannelIds) == 0 {
+		v.Add("channelIds", fmt.Errorf("channelIds is required"))
+	} else if len(req.ChannelIds) > MaxFeedChannels {
+		v.Add("channelIds", fmt.Errorf("at most %d channels can be listed", MaxFeedChannels))
+	}
+	v.Add("limit", ValidateLimit(req.Limit))
+	return v.Err()
+}
+
+// ValidateSearchVideos validates the query and the page of a search
+func ValidateSearchVideos(req *pb.SearchVideosRequest) error {
+	var v validation.Violations
+	if req.Query == "" {
+		v.Add("query", fmt.Errorf("query is required"))
+	} else if len(req.Query) > MaxQueryLength {
+		v.Add("query", fmt.Errorf("query is too long"))
+	}
+	v.Add("page", ValidatePage(req.Page))
+	v.Add("limit", ValidateLimit(req.Limit))
+	return v.Err()
+}